@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// auditEntry is one line of the -audit-log JSONL file
+type auditEntry struct {
+	Timestamp    time.Time `json:"timestamp"`
+	Event        string    `json:"event"`
+	RepoPath     string    `json:"repoPath"`
+	Branch       string    `json:"branch"`
+	CommitSHA    string    `json:"commitSha"`
+	PushedTo     []string  `json:"pushedTo,omitempty"`
+	FilesChanged int       `json:"filesChanged"`
+	Operator     string    `json:"operator"`
+}
+
+// auditLogger appends commit and push records to a JSONL file. Writes hold an flock on the
+// file, so several git-air instances can share one audit log.
+type auditLogger struct {
+	path    string
+	maxSize int64
+	mu      sync.Mutex
+}
+
+// audit is nil unless -audit-log is set
+var audit *auditLogger
+
+// auditOperator identifies this instance as "<hostname>:<pid>"
+func auditOperator() string {
+	hostname, _ := os.Hostname()
+	return hostname + ":" + strconv.Itoa(os.Getpid())
+}
+
+// recordAudit logs a commit or push of the current repo, if -audit-log is set
+func recordAudit(event string, pushedTo []string, filesChanged int) {
+	if audit == nil {
+		return
+	}
+	
+	entry := auditEntry{
+		Timestamp:    time.Now(),
+		RepoPath:     getCurrentDir(),
+		Branch:       getCurrentBranch(),
+		CommitSHA:    getHeadSHA(),
+		PushedTo:     pushedTo,
+		FilesChanged: filesChanged,
+		Operator:     auditOperator(),
+	}
+	var err error
+	if event == "push" {
+		err = audit.logPush(entry)
+	} else {
+		err = audit.logCommit(entry)
+	}
+	if err != nil {
+		fmt.Printf("  ⚠️  Writing audit log: %v\n", err)
+	}
+}
+
+func (a *auditLogger) logCommit(entry auditEntry) error {
+	entry.Event = "commit"
+	return a.write(entry)
+}
+
+func (a *auditLogger) logPush(entry auditEntry) error {
+	entry.Event = "push"
+	return a.write(entry)
+}
+
+// write appends entry as one JSON line, first rotating the file to <path>.1 when it
+// has reached -audit-log-max-size-mb
+func (a *auditLogger) write(entry auditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for {
+		file, err := os.OpenFile(a.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return err
+		}
+		if err := lockFile(file); err != nil {
+			file.Close()
+			return err
+		}
+		
+		// Another instance may have rotated the file while we waited for the lock
+		opened, err := file.Stat()
+		current, statErr := os.Stat(a.path)
+		if err != nil || statErr != nil || !os.SameFile(opened, current) {
+			file.Close()
+			continue
+		}
+		
+		if a.maxSize > 0 && opened.Size() >= a.maxSize {
+			err := os.Rename(a.path, a.path+".1")
+			file.Close()
+			if err != nil {
+				return fmt.Errorf("rotating %s: %v", a.path, err)
+			}
+			continue
+		}
+		
+		_, err = file.Write(line)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		return err
+	}
+}
\ No newline at end of file
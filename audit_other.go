@@ -0,0 +1,10 @@
+//go:build !unix
+
+package main
+
+import "os"
+
+// lockFile is not implemented on this platform; writes are only serialised within one instance
+func lockFile(f *os.File) error {
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// holdPushes makes processRepo commit without pushing or tagging while a -batch-mode batch is in progress
+var holdPushes bool
+
+// batchStarted is when the first change of the pending batch was seen, zero when nothing is pending
+var batchStarted time.Time
+
+// repoHasChanges reports whether the repo at repoPath has uncommitted changes
+func repoHasChanges(repoPath string) bool {
+	output, err := gitIn(repoPath, "status", "--porcelain").Output()
+	return err == nil && strings.TrimSpace(string(output)) != ""
+}
+
+// batchDue reports whether -batch-window has passed since the first change of the pending batch
+func batchDue(repos []string) bool {
+	if batchStarted.IsZero() {
+		for _, repo := range repos {
+			if repoHasChanges(repo) {
+				batchStarted = time.Now()
+				fmt.Printf("📦 Changes detected, committing them as one batch in %s\n", batchWindow)
+				break
+			}
+		}
+		if batchStarted.IsZero() {
+			return false
+		}
+	}
+	return time.Since(batchStarted) >= batchWindow
+}
+
+// batchCommit is a repo that committed in the pending batch, with its HEAD before the batch
+type batchCommit struct {
+	repo      string
+	before    string
+	releasing bool
+}
+
+// runBatch commits every changed repo in the order given, which is dependency order, and pushes
+// and tags them only once all commits succeeded. If a repo reports an error, the commits already
+// made in this batch are undone, keeping their changes staged, so the repos stay consistent with
+// each other.
+func runBatch(repos []string) error {
+	batchStarted = time.Time{}
+	
+	holdPushes = true
+	var committed []batchCommit
+	var failed error
+	for _, repo := range repos {
+		if !repoHasChanges(repo) {
+			continue
+		}
+		
+		before := headSHAIn(repo)
+		errorCount, _ := repoErrors(repo)
+		processRepo(repo)
+		// A repo can commit several times in one pass (-commit-prefix-by-file-type, submodules)
+		if headSHAIn(repo) != before {
+			committed = append(committed, batchCommit{repo, before, versionFileChangedSince(repo, before)})
+		}
+		if count, last := repoErrors(repo); count != errorCount {
+			failed = fmt.Errorf("%s: %s", filepath.Base(repo), last)
+			break
+		}
+	}
+	holdPushes = false
+	
+	if failed != nil {
+		rollbackBatch(committed)
+		return failed
+	}
+	for _, commit := range committed {
+		inRepo(commit.repo, func() {
+			pushRepo(commit.repo, 0, "batch commit")
+			tagCommit(commit.repo, commit.releasing)
+		})
+	}
+	if len(committed) > 0 {
+		fmt.Printf("📦 Batch of %d repos committed\n", len(committed))
+	}
+	return nil
+}
+
+// rollbackBatch resets each repo of a failed batch to its HEAD before the batch, newest first,
+// keeping the changes of the undone commits staged
+func rollbackBatch(committed []batchCommit) {
+	for i := len(committed) - 1; i >= 0; i-- {
+		commit := committed[i]
+		inRepo(commit.repo, func() {
+			if output, err := exec.Command("git", "reset", "--soft", commit.before).CombinedOutput(); err != nil {
+				fmt.Printf("  ⚠️  %s: Rolling back batch commits: %s\n", filepath.Base(commit.repo), gitErrorLine(output, err))
+				return
+			}
+			fmt.Printf("  ↩️  %s: Rolled back batch commits\n", filepath.Base(commit.repo))
+		})
+	}
+}
+
+// repoErrors returns how many errors were recorded for a repository and the most recent one.
+// Errors are recorded under the working directory of processRepo, so repoPath is resolved the same way.
+func repoErrors(repoPath string) (int, string) {
+	key, err := filepath.Abs(repoPath)
+	if err != nil {
+		return 0, ""
+	}
+	if resolved, err := filepath.EvalSymlinks(key); err == nil {
+		key = resolved
+	}
+	
+	count, last := 0, ""
+	state.update(key, func(repo *repoStatus) {
+		count = repo.ErrorCount
+		if len(repo.Errors) > 0 {
+			last = repo.Errors[len(repo.Errors)-1]
+		}
+	})
+	return count, last
+}
+
+// inRepo runs fn with the repo at repoPath as the current directory, holding syncMu
+func inRepo(repoPath string, fn func()) {
+	syncMu.Lock()
+	defer syncMu.Unlock()
+	
+	oldDir, _ := os.Getwd()
+	os.Chdir(repoPath)
+	defer os.Chdir(oldDir)
+	defer refreshRepoState()
+	fn()
+}
\ No newline at end of file
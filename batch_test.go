@@ -0,0 +1,50 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunBatchRollsBackWhenARepoFails(t *testing.T) {
+	// Use paths relative to the working directory, as a scan of "." yields
+	root := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(root)
+	defer os.Chdir(oldDir)
+	first, second := newTestRepo(t, "first"), newTestRepo(t, "second")
+	
+	firstHead := headSHAIn(first)
+	os.WriteFile(filepath.Join(first, "a.go"), []byte("package a\n"), 0644)
+	os.WriteFile(filepath.Join(first, "a.md"), []byte("# a\n"), 0644)
+	os.WriteFile(filepath.Join(second, "fail"), []byte("x\n"), 0644)
+	
+	// The hook rejects the commit in any repo containing a file named "fail"
+	hook := filepath.Join(root, "hook.sh")
+	os.WriteFile(hook, []byte("#!/bin/sh\ntest ! -e fail\n"), 0755)
+	preCommitHook, hookTimeout = hook, 10*time.Second
+	// Two commits in the first repo, and a checkpoint tag after each pass that commits
+	fileTypePrefixes = []fileTypePrefix{{"*.go", "code"}, {"*.md", "docs"}}
+	tagEvery, tagPrefix = 1, "checkpoint"
+	defer func() {
+		preCommitHook, hookTimeout, fileTypePrefixes, tagEvery, tagPrefix = "", 0, nil, 0, ""
+	}()
+	
+	if err := runBatch([]string{first, second}); err == nil {
+		t.Fatal("runBatch succeeded although the second repo's hook failed")
+	}
+	if head := headSHAIn(first); head != firstHead {
+		t.Errorf("first repo HEAD = %s, want the batch commits rolled back to %s", head, firstHead)
+	}
+	staged, _ := exec.Command("git", "-C", first, "diff", "--cached", "--name-only").Output()
+	if strings.Join(strings.Fields(string(staged)), " ") != "a.go a.md" {
+		t.Errorf("first repo staged files = %q, want the rolled back changes kept staged", staged)
+	}
+	tags, _ := exec.Command("git", "-C", first, "tag").Output()
+	if len(tags) > 0 {
+		t.Errorf("first repo tags = %q, want none while the batch is held back", tags)
+	}
+}
\ No newline at end of file
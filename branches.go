@@ -0,0 +1,244 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// branchInfo describes a local branch, with the remote branch it tracks, or an untracked remote branch
+type branchInfo struct {
+	Name           string    `json:"name"`
+	RemoteTracking string    `json:"remoteTracking,omitempty"`
+	LastCommitAt   time.Time `json:"lastCommitAt"`
+	IsLocal        bool      `json:"isLocal"`
+	IsRemote       bool      `json:"isRemote"`
+	IsHead         bool      `json:"isHead"`
+}
+
+// getBranchList lists the branches of the repository at repoPath, sorted by name
+func getBranchList(repoPath string) ([]branchInfo, error) {
+	output, err := gitIn(repoPath, "branch", "--all",
+		"--format=%(refname)|%(upstream:short)|%(committerdate:unix)|%(HEAD)").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git branch in %s: %v", repoPath, err)
+	}
+	return parseBranchList(string(output))
+}
+
+// parseBranchList parses "refname|upstream|unix time|HEAD marker" lines, folding remote
+// branches into the local branches tracking them
+func parseBranchList(output string) ([]branchInfo, error) {
+	var locals, remotes []branchInfo
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if line == "" {
+			continue
+		}
+		
+		fields := strings.Split(line, "|")
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("unexpected git branch line %q", line)
+		}
+		seconds, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			// Symbolic refs such as origin/HEAD have no commit date of their own
+			continue
+		}
+		
+		branch := branchInfo{LastCommitAt: time.Unix(seconds, 0), IsHead: fields[3] == "*"}
+		switch {
+		case strings.HasPrefix(fields[0], "refs/heads/"):
+			branch.Name = strings.TrimPrefix(fields[0], "refs/heads/")
+			branch.RemoteTracking = fields[1]
+			branch.IsLocal = true
+			locals = append(locals, branch)
+		case strings.HasPrefix(fields[0], "refs/remotes/"):
+			branch.Name = strings.TrimPrefix(fields[0], "refs/remotes/")
+			branch.IsRemote = true
+			remotes = append(remotes, branch)
+		}
+	}
+	
+	tracked := map[string]bool{}
+	for i, local := range locals {
+		for _, remote := range remotes {
+			if remote.Name == local.RemoteTracking {
+				locals[i].IsRemote = true
+				tracked[remote.Name] = true
+			}
+		}
+	}
+	branches := locals
+	for _, remote := range remotes {
+		if !tracked[remote.Name] {
+			branches = append(branches, remote)
+		}
+	}
+	sort.Slice(branches, func(i, j int) bool { return branches[i].Name < branches[j].Name })
+	return branches, nil
+}
+
+// runBranchesCommand implements "git-air branches", printing the branches of every
+// repository below the current directory as a table
+func runBranchesCommand(args []string) {
+	branchFlags := flag.NewFlagSet("branches", flag.ExitOnError)
+	branchFlags.Parse(args)
+	
+	repos, err := findGitRepos(".")
+	if err != nil {
+		log.Fatal(err)
+	}
+	
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "REPO\tBRANCH\tTRACKING\tWHERE\tLAST COMMIT")
+	for _, repo := range repos {
+		branches, err := getBranchList(repo)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+			continue
+		}
+		for _, branch := range branches {
+			name := branch.Name
+			if branch.IsHead {
+				name = "* " + name
+			}
+			where := "local"
+			if branch.IsLocal && branch.IsRemote {
+				where = "local+remote"
+			} else if branch.IsRemote {
+				where = "remote"
+			}
+			fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", filepath.Base(repo), name, branch.RemoteTracking, where,
+				branch.LastCommitAt.Format("2006-01-02 15:04"))
+		}
+	}
+	table.Flush()
+}
+
+// branchesHandler serves GET /branches/<repo>, identified by its directory name or full path
+func branchesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	
+	name := strings.TrimPrefix(r.URL.Path, "/branches/")
+	repoPath, ok := findRepo(name)
+	if !ok {
+		http.Error(w, "unknown repository "+name, http.StatusNotFound)
+		return
+	}
+	
+	branches, err := getBranchList(repoPath)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, branches)
+}
+
+// pruneStaleBranches drops remote-tracking refs deleted on remote, then deletes local branches
+// whose upstream is gone and that are merged into the remote's default branch. Protected
+// branches and the checked-out branch are never deleted.
+func pruneStaleBranches(remote string) ([]string, error) {
+	if output, err := runRemote(remote, "remote", "prune", remote); err != nil {
+		return nil, fmt.Errorf("git remote prune %s: %s", remote, gitErrorLine(output, err))
+	}
+	
+	// Merged means merged into what the remote calls HEAD, or the current branch if that's unknown
+	target := getCurrentBranch()
+	if output, err := exec.Command("git", "symbolic-ref", "--short", "refs/remotes/"+remote+"/HEAD").Output(); err == nil {
+		target = strings.TrimSpace(string(output))
+	} else if name, err := getRemoteDefaultBranch(remote); err == nil {
+		target = remote + "/" + name
+	}
+	
+	output, err := exec.Command("git", "branch", "--format=%(refname:short)|%(upstream:track)|%(HEAD)").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git branch: %v", err)
+	}
+	
+	var deleted []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Split(line, "|")
+		if len(fields) != 3 || fields[1] != "[gone]" || fields[2] == "*" {
+			continue
+		}
+		branch := fields[0]
+		if matchesBranchPattern(branch, protectedBranches) || !runGit("merge-base", "--is-ancestor", branch, target) {
+			continue
+		}
+		if dryRun {
+			fmt.Printf("  [DRY-RUN] Would delete merged branch %s\n", branch)
+			continue
+		}
+		if runGit("branch", "-d", branch) {
+			deleted = append(deleted, branch)
+		}
+	}
+	return deleted, nil
+}
+
+// cachedBranch is a remote's default branch as of a point in time
+type cachedBranch struct {
+	name    string
+	fetched time.Time
+}
+
+var (
+	defaultBranchMu    sync.Mutex
+	defaultBranchCache = map[string]cachedBranch{}
+)
+
+// getRemoteDefaultBranch returns the branch remote's HEAD points at, from git remote show.
+// Answers are cached per repo for -branch-cache-ttl since they need a round trip to the remote.
+func getRemoteDefaultBranch(remote string) (string, error) {
+	key := getCurrentDir() + "\x00" + remote
+	defaultBranchMu.Lock()
+	cached, ok := defaultBranchCache[key]
+	defaultBranchMu.Unlock()
+	if ok && time.Since(cached.fetched) < branchCacheTTL {
+		return cached.name, nil
+	}
+	
+	output, err := runRemote(remote, "remote", "show", remote)
+	if err != nil {
+		return "", fmt.Errorf("git remote show %s: %s", remote, gitErrorLine(output, err))
+	}
+	name, err := parseRemoteHeadBranch(string(output))
+	if err != nil {
+		return "", fmt.Errorf("%s: %v", remote, err)
+	}
+	
+	defaultBranchMu.Lock()
+	defaultBranchCache[key] = cachedBranch{name: name, fetched: time.Now()}
+	defaultBranchMu.Unlock()
+	return name, nil
+}
+
+// parseRemoteHeadBranch finds the "  HEAD branch: <name>" line of git remote show output
+func parseRemoteHeadBranch(output string) (string, error) {
+	for _, line := range strings.Split(output, "\n") {
+		name, ok := strings.CutPrefix(strings.TrimSpace(line), "HEAD branch:")
+		if !ok {
+			continue
+		}
+		name = strings.TrimSpace(name)
+		// git prints "(unknown)" for empty remotes and "(not queried)" with -n
+		if name == "" || strings.HasPrefix(name, "(") {
+			return "", fmt.Errorf("remote has no default branch")
+		}
+		return name, nil
+	}
+	return "", fmt.Errorf("no HEAD branch in git remote show output")
+}
\ No newline at end of file
@@ -0,0 +1,40 @@
+package main
+
+import "testing"
+
+func TestParseRemoteHeadBranch(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "default branch",
+			output: `* remote origin
+  Fetch URL: git@github.com:example/repo.git
+  Push  URL: git@github.com:example/repo.git
+  HEAD branch: main
+  Remote branches:
+    develop tracked
+    main    tracked
+`,
+			want: "main",
+		},
+		{name: "branch with slash", output: "  HEAD branch: release/2.x\n", want: "release/2.x"},
+		{name: "empty remote", output: "* remote origin\n  HEAD branch: (unknown)\n", wantErr: true},
+		{name: "not queried", output: "* remote origin\n  HEAD branch: (not queried)\n", wantErr: true},
+		{name: "no HEAD line", output: "* remote origin\n  Fetch URL: /tmp/repo.git\n", wantErr: true},
+		{name: "empty output", output: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseRemoteHeadBranch(tt.output)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// cherrySourceRemote is the temporary remote through which a commit of another repo is fetched
+const cherrySourceRemote = "_cherry_source"
+
+// cherryPickRequest is the body of POST /cherry-pick
+type cherryPickRequest struct {
+	Repo       string `json:"repo"`
+	SourcePath string `json:"sourcePath"`
+	CommitSHA  string `json:"commitSha"`
+}
+
+// cherryPickFrom applies commit sha of the repository at sourcePath on top of the current repo,
+// committing as the auto-commit identity. A conflicting cherry-pick is aborted.
+func cherryPickFrom(sourcePath, sha string) error {
+	// Left over if git-air was killed mid cherry-pick
+	runGit("remote", "remove", cherrySourceRemote)
+	
+	if output, err := exec.Command("git", "remote", "add", cherrySourceRemote, sourcePath).CombinedOutput(); err != nil {
+		return fmt.Errorf("git remote add: %s", gitErrorLine(output, err))
+	}
+	defer runGit("remote", "remove", cherrySourceRemote)
+	
+	if output, err := exec.Command("git", "fetch", "--no-tags", cherrySourceRemote, sha).CombinedOutput(); err != nil {
+		return fmt.Errorf("fetching %s from %s: %s", sha, sourcePath, gitErrorLine(output, err))
+	}
+	
+	args := append(authorArgs(), "cherry-pick", sha)
+	if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		runGit("cherry-pick", "--abort")
+		return fmt.Errorf("cherry-pick %s: %s", sha, gitErrorLine(output, err))
+	}
+	return nil
+}
+
+// cherryPickHandler serves POST /cherry-pick with {"repo", "sourcePath", "commitSha"}, sourcePath
+// lying inside the scan root. It answers 409 while syncing is paused or left to another instance,
+// and instead of waiting while a commit or pull of any repo is in progress.
+func cherryPickHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	
+	var req cherryPickRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	repoPath, ok := findRepo(req.Repo)
+	if !ok {
+		http.Error(w, "unknown repository "+req.Repo, http.StatusNotFound)
+		return
+	}
+	if req.SourcePath == "" || req.CommitSHA == "" {
+		http.Error(w, "sourcePath and commitSha are required", http.StatusBadRequest)
+		return
+	}
+	// Relative source paths are relative to the scan root, not to the target repo or the working
+	// directory, which other handlers change while they hold syncMu
+	sourcePath := req.SourcePath
+	if !filepath.IsAbs(sourcePath) {
+		sourcePath = filepath.Join(scanRoot, sourcePath)
+	}
+	sourcePath = filepath.Clean(sourcePath)
+	if sourcePath != scanRoot && !isInside(sourcePath, scanRoot) {
+		http.Error(w, "sourcePath must be inside "+scanRoot, http.StatusBadRequest)
+		return
+	}
+	
+	if err := syncBlocked(); err != nil {
+		http.Error(w, "not cherry-picked: "+err.Error(), http.StatusConflict)
+		return
+	}
+	if !syncMu.TryLock() {
+		http.Error(w, "a sync is in progress, try again shortly", http.StatusConflict)
+		return
+	}
+	defer syncMu.Unlock()
+	
+	oldDir, _ := os.Getwd()
+	os.Chdir(repoPath)
+	defer os.Chdir(oldDir)
+	
+	if err := cherryPickFrom(sourcePath, req.CommitSHA); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	fmt.Printf("🍒 %s: Cherry-picked %.7s from %s\n", filepath.Base(repoPath), req.CommitSHA, req.SourcePath)
+	writeJSON(w, map[string]string{"repo": repoPath, "commitSha": getHeadSHA()})
+}
\ No newline at end of file
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCherryPickHandler(t *testing.T) {
+	root := t.TempDir()
+	target := newTestRepo(t, filepath.Join(root, "api"))
+	source := newTestRepo(t, filepath.Join(root, "web"))
+	commitTestFile(t, source, "fix.txt", "fix\n")
+	sha := headSHAIn(source)
+	outside := newTestRepo(t, filepath.Join(t.TempDir(), "other"))
+	
+	oldRoot := scanRoot
+	scanRoot = root
+	defer func() { scanRoot = oldRoot }()
+	state.update(target, func(*repoStatus) {})
+	defer state.forget(target)
+	
+	post := func(sourcePath string) *httptest.ResponseRecorder {
+		body := `{"repo":"api","sourcePath":"` + sourcePath + `","commitSha":"` + sha + `"}`
+		w := httptest.NewRecorder()
+		cherryPickHandler(w, httptest.NewRequest(http.MethodPost, "/cherry-pick", strings.NewReader(body)))
+		return w
+	}
+	
+	for _, sourcePath := range []string{outside, "../" + filepath.Base(filepath.Dir(outside)) + "/other", "/etc"} {
+		if w := post(sourcePath); w.Code != http.StatusBadRequest {
+			t.Errorf("sourcePath %q: status %d, want %d", sourcePath, w.Code, http.StatusBadRequest)
+		}
+	}
+	
+	pause.set(time.Minute)
+	w := post("web")
+	pause.resume()
+	if w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), errSyncPaused.Error()) {
+		t.Errorf("while paused: status %d %q, want %d with the pause reason", w.Code, w.Body.String(), http.StatusConflict)
+	}
+	
+	if w := post("web"); w.Code != http.StatusOK {
+		t.Fatalf("status %d %q, want %d", w.Code, w.Body.String(), http.StatusOK)
+	}
+	if _, err := os.Stat(filepath.Join(target, "fix.txt")); err != nil {
+		t.Errorf("cherry-picked file missing: %v", err)
+	}
+}
\ No newline at end of file
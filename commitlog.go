@@ -0,0 +1,273 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// commitInfo is one entry of a repository's commit history
+type commitInfo struct {
+	SHA       string    `json:"sha"`
+	Author    string    `json:"author"`
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// getLog returns the n most recent auto-commits of the repository at repoPath, the ones carrying
+// autoCommitTrailer. It runs git with cmd.Dir instead of chdir so it is safe to call from the
+// status server.
+func getLog(repoPath string, n int) ([]commitInfo, error) {
+	cmd := exec.Command("git", "log", "-n", strconv.Itoa(n), "--extended-regexp", "--grep=^"+autoCommitTrailer+"$",
+		"--format=%H|%an|%ct|%s")
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log in %s: %v", repoPath, err)
+	}
+	return parseLog(string(output))
+}
+
+// getUnpushedCommits returns the commits of the current repo that remote/branch doesn't have yet
+func getUnpushedCommits(remote, branch string) ([]commitInfo, error) {
+	cmd := exec.Command("git", "log", remote+"/"+branch+"..HEAD", "--format=%H|%an|%ct|%s")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log %s/%s..HEAD: %v", remote, branch, err)
+	}
+	return parseLog(string(output))
+}
+
+// parseLog parses "sha|author|unix time|subject" lines; the subject comes last so it may contain "|"
+func parseLog(output string) ([]commitInfo, error) {
+	commits := []commitInfo{}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if line == "" {
+			continue
+		}
+		
+		fields := strings.SplitN(line, "|", 4)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("unexpected git log line %q", line)
+		}
+		seconds, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected commit time in %q", line)
+		}
+		
+		commits = append(commits, commitInfo{
+			SHA:       fields[0],
+			Author:    fields[1],
+			Message:   fields[3],
+			Timestamp: time.Unix(seconds, 0),
+		})
+	}
+	return commits, nil
+}
+
+// runLogCommand implements "git-air log [-n count]", printing the recent auto-commits
+// of every repository below the current directory as a table
+func runLogCommand(args []string) {
+	logFlags := flag.NewFlagSet("log", flag.ExitOnError)
+	count := logFlags.Int("n", 10, "Number of auto-commits to show per repository")
+	logFlags.Parse(args)
+	
+	repos, err := findGitRepos(".")
+	if err != nil {
+		log.Fatal(err)
+	}
+	
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "REPO\tSHA\tAUTHOR\tTIME\tMESSAGE")
+	for _, repo := range repos {
+		commits, err := getLog(repo, *count)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+			continue
+		}
+		for _, commit := range commits {
+			fmt.Fprintf(table, "%s\t%.7s\t%s\t%s\t%s\n", filepath.Base(repo), commit.SHA, commit.Author,
+				commit.Timestamp.Format("2006-01-02 15:04"), commit.Message)
+		}
+	}
+	table.Flush()
+}
+
+// repoLogHandler serves GET /status/log/<repo> with the last 10 auto-commits of a repository,
+// identified by its directory name or full path
+func repoLogHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	
+	name := strings.TrimPrefix(r.URL.Path, "/status/log/")
+	repoPath, ok := findRepo(name)
+	if !ok {
+		http.Error(w, "unknown repository "+name, http.StatusNotFound)
+		return
+	}
+	
+	commits, err := getLog(repoPath, 10)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, commits)
+}
+
+// fileCommit is one commit in the history of a single file
+type fileCommit struct {
+	SHA          string    `json:"sha"`
+	Author       string    `json:"author"`
+	Message      string    `json:"message"`
+	Timestamp    time.Time `json:"timestamp"`
+	LinesAdded   int       `json:"linesAdded"`
+	LinesDeleted int       `json:"linesDeleted"`
+}
+
+// getFileHistory returns the n most recent commits touching file in the repository at repoPath,
+// following renames
+func getFileHistory(repoPath, file string, n int) ([]fileCommit, error) {
+	output, err := gitIn(repoPath, "log", "-n", strconv.Itoa(n), "--follow", "--numstat",
+		"--format=%x00%H|%an|%ct|%s", "--", file).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log %s in %s: %v", file, repoPath, err)
+	}
+	return parseFileHistory(string(output))
+}
+
+// parseFileHistory parses git log records that start with NUL and a "sha|author|unix time|subject"
+// line, followed by the --numstat line of the file
+func parseFileHistory(output string) ([]fileCommit, error) {
+	commits := []fileCommit{}
+	for _, record := range strings.Split(output, "\x00") {
+		lines := strings.Split(strings.TrimSpace(record), "\n")
+		if lines[0] == "" {
+			continue
+		}
+		
+		parsed, err := parseLog(lines[0])
+		if err != nil || len(parsed) != 1 {
+			return nil, fmt.Errorf("unexpected git log record %q", record)
+		}
+		commit := fileCommit{SHA: parsed[0].SHA, Author: parsed[0].Author, Message: parsed[0].Message, Timestamp: parsed[0].Timestamp}
+		for _, line := range lines[1:] {
+			fields := strings.SplitN(line, "\t", 3)
+			if len(fields) == 3 {
+				// Binary files report "-", which counts as no lines
+				added, _ := strconv.Atoi(fields[0])
+				deleted, _ := strconv.Atoi(fields[1])
+				commit.LinesAdded += added
+				commit.LinesDeleted += deleted
+			}
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+// blameLine attributes one line of a file to the commit that last changed it
+type blameLine struct {
+	Line      int       `json:"line"`
+	SHA       string    `json:"sha"`
+	Author    string    `json:"author"`
+	Timestamp time.Time `json:"timestamp"`
+	Content   string    `json:"content"`
+}
+
+// getFileBlame runs git blame on file in the repository at repoPath
+func getFileBlame(repoPath, file string) ([]blameLine, error) {
+	output, err := gitIn(repoPath, "blame", "--porcelain", "--", file).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git blame %s in %s: %v", file, repoPath, err)
+	}
+	return parseBlame(string(output)), nil
+}
+
+// parseBlame parses git blame --porcelain output, where author details are only given
+// the first time each commit appears
+func parseBlame(output string) []blameLine {
+	authors := map[string]string{}
+	times := map[string]time.Time{}
+	
+	lines := []blameLine{}
+	var current blameLine
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "\t") {
+			current.Content = line[1:]
+			current.Author = authors[current.SHA]
+			current.Timestamp = times[current.SHA]
+			lines = append(lines, current)
+			continue
+		}
+		
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) >= 3 && (len(fields[0]) == 40 || len(fields[0]) == 64):
+			number, _ := strconv.Atoi(fields[2])
+			current = blameLine{SHA: fields[0], Line: number}
+		case strings.HasPrefix(line, "author "):
+			authors[current.SHA] = strings.TrimPrefix(line, "author ")
+		case strings.HasPrefix(line, "author-time "):
+			if seconds, err := strconv.ParseInt(strings.TrimPrefix(line, "author-time "), 10, 64); err == nil {
+				times[current.SHA] = time.Unix(seconds, 0)
+			}
+		}
+	}
+	return lines
+}
+
+// fileHistoryHandler serves GET /history/<repo>?file=<path>&n=10 and GET /blame/<repo>?file=<path>
+func fileHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	
+	blame := strings.HasPrefix(r.URL.Path, "/blame/")
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/blame/"), "/history/")
+	repoPath, ok := findRepo(name)
+	if !ok {
+		http.Error(w, "unknown repository "+name, http.StatusNotFound)
+		return
+	}
+	file := r.URL.Query().Get("file")
+	if file == "" {
+		http.Error(w, "file is required", http.StatusBadRequest)
+		return
+	}
+	
+	if blame {
+		lines, err := getFileBlame(repoPath, file)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		writeJSON(w, lines)
+		return
+	}
+	
+	n := 10
+	if value := r.URL.Query().Get("n"); value != "" {
+		var err error
+		if n, err = strconv.Atoi(value); err != nil || n <= 0 {
+			http.Error(w, "invalid n "+value, http.StatusBadRequest)
+			return
+		}
+	}
+	commits, err := getFileHistory(repoPath, file, n)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, commits)
+}
\ No newline at end of file
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGetLogListsOnlyAutoCommits(t *testing.T) {
+	dir := newTestRepo(t, filepath.Join(t.TempDir(), "repo"))
+	commit := func(args ...string) {
+		os.WriteFile(filepath.Join(dir, "file.txt"), []byte(filepath.Join(args...)), 0644)
+		runTestGit(t, dir, "add", "file.txt")
+		runTestGit(t, dir, args...)
+	}
+	commit(commitArgs("auto commit - 1 files changed | first")...)
+	commit("commit", "-q", "-m", "Fix the parser by hand")
+	commit(commitArgs("docs(auto): 1 files changed")...)
+	// Mentioning the trailer in the middle of a line doesn't make a commit an auto-commit
+	commit("commit", "-q", "-m", "Revert the change", "-m", "The Git-Air: auto commit was wrong")
+	
+	commits, err := getLog(dir, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var messages []string
+	for _, c := range commits {
+		messages = append(messages, c.Message)
+	}
+	want := []string{"docs(auto): 1 files changed", "auto commit - 1 files changed | first"}
+	if !reflect.DeepEqual(messages, want) {
+		t.Errorf("getLog() messages = %q, want %q", messages, want)
+	}
+	
+	if commits, _ := getLog(dir, 1); len(commits) != 1 || commits[0].Message != want[0] {
+		t.Errorf("getLog(1) = %+v, want only the newest auto-commit", commits)
+	}
+}
\ No newline at end of file
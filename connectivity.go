@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// connectivityErrorType classifies why a remote couldn't be reached
+type connectivityErrorType string
+
+const (
+	connectivityDNS     connectivityErrorType = "dns"
+	connectivityAuth    connectivityErrorType = "auth"
+	connectivityTimeout connectivityErrorType = "timeout"
+	connectivityServer  connectivityErrorType = "server"
+	connectivityUnknown connectivityErrorType = "unknown"
+)
+
+// connectivityPatterns maps git/ssh/curl error output to the failure it indicates
+var connectivityPatterns = []struct {
+	text      string
+	errorType connectivityErrorType
+}{
+	{"could not resolve host", connectivityDNS},
+	{"could not resolve hostname", connectivityDNS},
+	{"name or service not known", connectivityDNS},
+	{"temporary failure in name resolution", connectivityDNS},
+	{"authentication failed", connectivityAuth},
+	{"permission denied", connectivityAuth},
+	{"could not read username", connectivityAuth},
+	{"host key verification failed", connectivityAuth},
+	{"returned error: 401", connectivityAuth},
+	{"returned error: 403", connectivityAuth},
+	{"timed out", connectivityTimeout},
+	{"connection refused", connectivityServer},
+	{"returned error: 5", connectivityServer},
+	{"does not appear to be a git repository", connectivityServer},
+	{"repository not found", connectivityServer},
+}
+
+// connectivityHints tells the user what to check for each kind of failure
+var connectivityHints = map[connectivityErrorType]string{
+	connectivityDNS:     "check the remote URL and DNS",
+	connectivityAuth:    "check credentials or -remote-ssh-keys",
+	connectivityTimeout: "no answer within -network-timeout",
+	connectivityServer:  "the server rejected the request",
+	connectivityUnknown: "see the message",
+}
+
+// remoteConnectivityError explains why a remote couldn't be reached
+type remoteConnectivityError struct {
+	Remote    string
+	Message   string
+	ErrorType connectivityErrorType
+}
+
+func (e *remoteConnectivityError) Error() string {
+	return fmt.Sprintf("remote %s not reachable (%s: %s): %s", e.Remote, e.ErrorType, connectivityHints[e.ErrorType], e.Message)
+}
+
+// verifyRemoteConnectivity checks that a remote answers git ls-remote within -network-timeout,
+// returning a *remoteConnectivityError saying why when it doesn't. Always nil in -offline-mode.
+func verifyRemoteConnectivity(remote string) error {
+	if offlineMode {
+		return nil
+	}
+	
+	acquireOpSlot(context.Background())
+	defer releaseOpSlot()
+	
+	ctx, cancel := context.WithTimeout(context.Background(), networkTimeout)
+	defer cancel()
+	
+	cmd := withSSHKey(exec.CommandContext(ctx, "git", "ls-remote", "--exit-code", remote, "HEAD"), remote)
+	cmd.WaitDelay = time.Second
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 2 {
+		return nil // Reachable, just no HEAD yet (empty repository)
+	}
+	
+	connErr := &remoteConnectivityError{Remote: remote, Message: gitErrorLine(output, err)}
+	if ctx.Err() == context.DeadlineExceeded {
+		connErr.ErrorType = connectivityTimeout
+	} else {
+		connErr.ErrorType = classifyConnectivityError(string(output))
+	}
+	return connErr
+}
+
+// classifyConnectivityError recognises the failure behind git ls-remote error output
+func classifyConnectivityError(output string) connectivityErrorType {
+	output = strings.ToLower(output)
+	for _, pattern := range connectivityPatterns {
+		if strings.Contains(output, pattern.text) {
+			return pattern.errorType
+		}
+	}
+	return connectivityUnknown
+}
+
+// checkRemote verifies remote is reachable, recording the failure type in the status API
+func checkRemote(remote string) error {
+	err := verifyRemoteConnectivity(remote)
+	state.update(getCurrentDir(), func(repo *repoStatus) {
+		if connErr, ok := err.(*remoteConnectivityError); ok {
+			if repo.UnreachableRemotes == nil {
+				repo.UnreachableRemotes = map[string]connectivityErrorType{}
+			}
+			repo.UnreachableRemotes[remote] = connErr.ErrorType
+		} else {
+			delete(repo.UnreachableRemotes, remote)
+		}
+	})
+	return err
+}
\ No newline at end of file
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// activeWindow is how recently someone must have committed to count as an active contributor
+const activeWindow = 30 * 24 * time.Hour
+
+// contributor summarises one author's commits to a repository
+type contributor struct {
+	Name         string    `json:"name"`
+	Email        string    `json:"email"`
+	CommitCount  int       `json:"commitCount"`
+	LastCommitAt time.Time `json:"lastCommitAt"`
+}
+
+// getContributors returns everyone who committed to any branch of the repo at repoPath, most
+// commits first. It reads git log rather than git shortlog -sne, which can't give the last commit time.
+func getContributors(repoPath string) ([]contributor, error) {
+	output, err := gitIn(repoPath, "log", "--all", "--format=%aN|%aE|%ct").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log in %s: %v", repoPath, err)
+	}
+	return parseContributors(string(output)), nil
+}
+
+// parseContributors groups "name|email|unix time" lines by email
+func parseContributors(output string) []contributor {
+	byEmail := map[string]*contributor{}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Split(line, "|")
+		if len(fields) != 3 {
+			continue
+		}
+		seconds, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		
+		key := strings.ToLower(fields[1])
+		c, ok := byEmail[key]
+		if !ok {
+			c = &contributor{Name: fields[0], Email: fields[1]}
+			byEmail[key] = c
+		}
+		c.CommitCount++
+		if at := time.Unix(seconds, 0); at.After(c.LastCommitAt) {
+			c.LastCommitAt = at
+		}
+	}
+	
+	contributors := make([]contributor, 0, len(byEmail))
+	for _, c := range byEmail {
+		contributors = append(contributors, *c)
+	}
+	sort.Slice(contributors, func(i, j int) bool {
+		if contributors[i].CommitCount != contributors[j].CommitCount {
+			return contributors[i].CommitCount > contributors[j].CommitCount
+		}
+		return contributors[i].Email < contributors[j].Email
+	})
+	return contributors
+}
+
+// contributorsHandler serves GET /contributors/<repo>
+func contributorsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	
+	name := strings.TrimPrefix(r.URL.Path, "/contributors/")
+	repoPath, ok := findRepo(name)
+	if !ok {
+		http.Error(w, "unknown repository "+name, http.StatusNotFound)
+		return
+	}
+	
+	contributors, err := getContributors(repoPath)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, contributors)
+}
+
+// isGitAirAuthor reports whether name/email is the identity git-air commits as
+func isGitAirAuthor(name, email string) bool {
+	return commitAuthorEmail != "" && strings.EqualFold(email, commitAuthorEmail) ||
+		commitAuthorName != "" && name == commitAuthorName
+}
+
+// countActiveContributors counts the people other than git-air who committed to the repo at
+// repoPath within the last 30 days. Commits carrying autoCommitTrailer are left out, as they
+// are authored as the repo's user unless -commit-author-name/-email are set.
+func countActiveContributors(repoPath string) int {
+	since := strconv.FormatInt(time.Now().Add(-activeWindow).Unix(), 10)
+	output, err := gitIn(repoPath, "log", "--all", "--since="+since, "--invert-grep", "--grep=^"+autoCommitTrailer+"$",
+		"--format=%aN|%aE|%ct").Output()
+	if err != nil {
+		return 0
+	}
+	
+	active := 0
+	for _, c := range parseContributors(string(output)) {
+		if !isGitAirAuthor(c.Name, c.Email) {
+			active++
+		}
+	}
+	return active
+}
+
+// getLastHumanCommitTime returns when someone other than git-air last committed to the current
+// branch. Only the latest 500 commits are read; if none of them is by a person, the oldest one
+// read is returned, so a repo git-air alone has been committing to counts as idle since then.
+func getLastHumanCommitTime() time.Time {
+	// Records are NUL-terminated because the full message, which holds the trailer, spans lines
+	cmd := exec.Command("git", "log", "-n", "500", "--format=%aN|%aE|%ct|%B%x00")
+	output, err := cmd.Output()
+	if err != nil {
+		return time.Time{}
+	}
+	
+	var oldest time.Time
+	for _, record := range strings.Split(string(output), "\x00") {
+		fields := strings.SplitN(strings.TrimSpace(record), "|", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		seconds, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		oldest = time.Unix(seconds, 0)
+		if !isGitAirAuthor(fields[0], fields[1]) && !isAutoCommitMessage(fields[3]) {
+			return oldest
+		}
+	}
+	return oldest
+}
\ No newline at end of file
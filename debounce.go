@@ -0,0 +1,29 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// changedWithin reports whether a modified or untracked file of the current repo was written less
+// than window ago, i.e. whether a burst of saves (a formatter rewriting a directory) is still going on
+func changedWithin(window time.Duration) bool {
+	if window <= 0 {
+		return false
+	}
+	output, err := exec.Command("git", "ls-files", "-z", "--modified", "--others", "--exclude-standard").Output()
+	if err != nil {
+		return false
+	}
+	for _, file := range strings.Split(string(output), "\x00") {
+		if file == "" {
+			continue
+		}
+		if info, err := os.Lstat(file); err == nil && time.Since(info.ModTime()) < window {
+			return true
+		}
+	}
+	return false
+}
\ No newline at end of file
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestChangedWithin(t *testing.T) {
+	dir := newTestRepo(t, filepath.Join(t.TempDir(), "repo"))
+	oldDir, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(oldDir)
+	
+	if changedWithin(time.Minute) {
+		t.Error("changedWithin() = true without changes")
+	}
+	
+	os.WriteFile("saved.txt", []byte("x\n"), 0644)
+	if !changedWithin(time.Minute) {
+		t.Error("changedWithin() = false right after a save")
+	}
+	if changedWithin(0) {
+		t.Error("changedWithin(0) = true, want debouncing disabled")
+	}
+	
+	settled := time.Now().Add(-2 * time.Minute)
+	os.Chtimes("saved.txt", settled, settled)
+	if changedWithin(time.Minute) {
+		t.Error("changedWithin() = true for a file left alone longer than the window")
+	}
+}
\ No newline at end of file
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"os"
+	"strings"
+)
+
+// defaultCIEnvVars are set by common CI systems; -ci-env-vars adds more
+var defaultCIEnvVars = []string{"CI", "GITHUB_ACTIONS", "GITLAB_CI", "JENKINS_HOME"}
+
+// ciEnvVars are the variables whose presence marks a CI environment
+var ciEnvVars = defaultCIEnvVars
+
+// isRunningInCI reports whether git-air looks like it was started by a CI job
+func isRunningInCI() bool {
+	return ciMarker() != ""
+}
+
+// ciMarker returns the first CI variable that is set, ignoring empty, "0" and "false" values
+func ciMarker() string {
+	for _, name := range ciEnvVars {
+		switch strings.ToLower(os.Getenv(name)) {
+		case "", "0", "false":
+			continue
+		}
+		return name
+	}
+	return ""
+}
\ No newline at end of file
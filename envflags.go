@@ -0,0 +1,37 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// flagEnvPrefix starts the environment variable of every option, e.g. GIT_AIR_DEBOUNCE_WINDOW for -debounce-window
+const flagEnvPrefix = "GIT_AIR_"
+
+// flagEnvName returns the environment variable that sets the option called name
+func flagEnvName(name string) string {
+	return flagEnvPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// applyEnvOverrides sets each option of fs that wasn't given on the command line from its
+// GIT_AIR_* variable, so containers can configure git-air without arguments. Command line flags
+// win over the environment; every value that doesn't parse is reported.
+func applyEnvOverrides(fs *flag.FlagSet) error {
+	given := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { given[f.Name] = true })
+	
+	var errs []error
+	fs.VisitAll(func(f *flag.Flag) {
+		value, ok := os.LookupEnv(flagEnvName(f.Name))
+		if !ok || given[f.Name] {
+			return
+		}
+		if err := f.Value.Set(value); err != nil {
+			errs = append(errs, fmt.Errorf("$%s=%q: %v", flagEnvName(f.Name), value, err))
+		}
+	})
+	return errors.Join(errs...)
+}
\ No newline at end of file
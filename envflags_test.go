@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestApplyEnvOverrides(t *testing.T) {
+	fs := flag.NewFlagSet("git-air", flag.ContinueOnError)
+	strategy := fs.String("pull-strategy", "merge", "")
+	dryRun := fs.Bool("dry-run", false, "")
+	window := fs.Duration("debounce-window", 2*time.Second, "")
+	retries := fs.Int("push-retry-attempts", 3, "")
+	if err := fs.Parse([]string{"-push-retry-attempts", "5"}); err != nil {
+		t.Fatal(err)
+	}
+	
+	t.Setenv("GIT_AIR_PULL_STRATEGY", "rebase")
+	t.Setenv("GIT_AIR_DRY_RUN", "true")
+	t.Setenv("GIT_AIR_DEBOUNCE_WINDOW", "10s")
+	t.Setenv("GIT_AIR_PUSH_RETRY_ATTEMPTS", "1")
+	if err := applyEnvOverrides(fs); err != nil {
+		t.Fatal(err)
+	}
+	if *strategy != "rebase" || !*dryRun || *window != 10*time.Second {
+		t.Errorf("got -pull-strategy %q -dry-run %v -debounce-window %s, want the environment's rebase, true, 10s", *strategy, *dryRun, *window)
+	}
+	if *retries != 5 {
+		t.Errorf("-push-retry-attempts = %d, want the command line's 5 over the environment", *retries)
+	}
+	
+	t.Setenv("GIT_AIR_DEBOUNCE_WINDOW", "soon")
+	t.Setenv("GIT_AIR_DRY_RUN", "maybe")
+	err := applyEnvOverrides(fs)
+	if err == nil || !strings.Contains(err.Error(), "GIT_AIR_DEBOUNCE_WINDOW") || !strings.Contains(err.Error(), "GIT_AIR_DRY_RUN") {
+		t.Errorf("applyEnvOverrides() = %v, want both invalid variables reported", err)
+	}
+}
\ No newline at end of file
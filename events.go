@@ -0,0 +1,73 @@
+package main
+
+import "log"
+
+// eventHandler is notified of what git-air does to each repository. Register one with
+// addHandler to hook in without touching the sync code.
+type eventHandler interface {
+	onCommit(repo, sha, message string)
+	onPush(repo, remote, branch string)
+	onPullComplete(repo, remote string)
+	onError(repo string, err error)
+}
+
+// noopHandler ignores every event; embed it to implement only the events you need
+type noopHandler struct{}
+
+func (noopHandler) onCommit(repo, sha, message string) {}
+func (noopHandler) onPush(repo, remote, branch string) {}
+func (noopHandler) onPullComplete(repo, remote string) {}
+func (noopHandler) onError(repo string, err error)     {}
+
+// logHandler writes every event to the standard logger, for -log-events
+type logHandler struct{}
+
+func (logHandler) onCommit(repo, sha, message string) {
+	log.Printf("event=commit repo=%s sha=%.7s message=%q", repo, sha, message)
+}
+
+func (logHandler) onPush(repo, remote, branch string) {
+	log.Printf("event=push repo=%s remote=%s branch=%s", repo, remote, branch)
+}
+
+func (logHandler) onPullComplete(repo, remote string) {
+	log.Printf("event=pull repo=%s remote=%s", repo, remote)
+}
+
+func (logHandler) onError(repo string, err error) {
+	log.Printf("event=error repo=%s error=%q", repo, err)
+}
+
+// handlerChain passes each event to every handler in registration order
+type handlerChain []eventHandler
+
+// handlers are registered at startup, before any repo is processed
+var handlers handlerChain
+
+func addHandler(h eventHandler) {
+	handlers = append(handlers, h)
+}
+
+func (c handlerChain) onCommit(repo, sha, message string) {
+	for _, h := range c {
+		h.onCommit(repo, sha, message)
+	}
+}
+
+func (c handlerChain) onPush(repo, remote, branch string) {
+	for _, h := range c {
+		h.onPush(repo, remote, branch)
+	}
+}
+
+func (c handlerChain) onPullComplete(repo, remote string) {
+	for _, h := range c {
+		h.onPullComplete(repo, remote)
+	}
+}
+
+func (c handlerChain) onError(repo string, err error) {
+	for _, h := range c {
+		h.onError(repo, err)
+	}
+}
\ No newline at end of file
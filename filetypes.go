@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// fileTypePrefix gives files matching Pattern their own commits, with messages starting "<Prefix>: "
+type fileTypePrefix struct {
+	Pattern string
+	Prefix  string
+}
+
+// parseFileTypePrefixes parses comma-separated pattern=prefix pairs, e.g. "*.go=refactor,*.md=docs"
+func parseFileTypePrefixes(value string) ([]fileTypePrefix, error) {
+	var prefixes []fileTypePrefix
+	for _, pair := range splitList(value) {
+		pattern, prefix, ok := strings.Cut(pair, "=")
+		if !ok || pattern == "" || prefix == "" {
+			return nil, fmt.Errorf("%q must look like pattern=prefix", pair)
+		}
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %v", pattern, err)
+		}
+		prefixes = append(prefixes, fileTypePrefix{Pattern: pattern, Prefix: prefix})
+	}
+	return prefixes, nil
+}
+
+// prefixForFile returns the prefix of the first pattern matching path, or "" for none.
+// Patterns without a slash match the file name in any directory, like .gitignore.
+func prefixForFile(path string, prefixes []fileTypePrefix) string {
+	for _, p := range prefixes {
+		if !strings.Contains(p.Pattern, "/") {
+			if matched, _ := filepath.Match(p.Pattern, filepath.Base(path)); matched {
+				return p.Prefix
+			}
+		} else if matchPathPattern(p.Pattern, path) {
+			return p.Prefix
+		}
+	}
+	return ""
+}
+
+// groupFilesByPrefix splits files by their commit message prefix; unmatched files are grouped under ""
+func groupFilesByPrefix(files []string, prefixes []fileTypePrefix) map[string][]string {
+	groups := map[string][]string{}
+	for _, file := range files {
+		prefix := prefixForFile(file, prefixes)
+		groups[prefix] = append(groups[prefix], file)
+	}
+	return groups
+}
+
+// stagedFiles lists the paths staged for the next commit
+func stagedFiles() []string {
+	cmd := exec.Command("git", "diff", "--cached", "--name-only", "--no-renames", "-z")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	
+	var files []string
+	for _, file := range strings.Split(string(output), "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// commitByFileType makes one commit per -file-type-prefixes group of the staged files,
+// prefixing message with the group's prefix. Files matching no pattern get message as is.
+func commitByFileType(message string) bool {
+	groups := groupFilesByPrefix(stagedFiles(), fileTypePrefixes)
+	prefixes := make([]string, 0, len(groups))
+	for prefix := range groups {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	
+	committed := false
+	for _, prefix := range prefixes {
+		groupMessage := message
+		if prefix != "" {
+			groupMessage = prefix + ": " + message
+		}
+		args := append(commitArgs(groupMessage), "--")
+		if runGit(append(args, groups[prefix]...)...) {
+			committed = true
+		} else {
+			fmt.Printf("  ❌ Commit of %s files failed\n", strings.Join(groups[prefix], ", "))
+		}
+	}
+	return committed
+}
\ No newline at end of file
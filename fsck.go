@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// fsckIssue is one problem git fsck found, or a detached HEAD
+type fsckIssue struct {
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// repoHealth is the GET /health entry of one repository
+type repoHealth struct {
+	Path      string      `json:"path"`
+	Status    string      `json:"status"`
+	CheckedAt time.Time   `json:"checkedAt"`
+	Issues    []fsckIssue `json:"issues"`
+}
+
+// runFsck checks the object database and refs of the repo at repoPath
+func runFsck(repoPath string) []fsckIssue {
+	output, err := gitIn(repoPath, "fsck", "--no-progress").CombinedOutput()
+	issues := parseFsck(string(output))
+	if err != nil && !hasErrors(issues) {
+		// git fsck failed without saying why in a form parseFsck knows
+		issues = append(issues, fsckIssue{Severity: "error", Message: gitErrorLine(output, err)})
+	}
+	if gitIn(repoPath, "symbolic-ref", "-q", "HEAD").Run() != nil {
+		issues = append(issues, fsckIssue{Severity: "warning", Message: "HEAD is detached"})
+	}
+	return issues
+}
+
+// parseFsck sorts git fsck output into errors (corrupt or missing objects, broken links, bad refs)
+// and warnings. Dangling objects are normal leftovers and are skipped.
+func parseFsck(output string) []fsckIssue {
+	var issues []fsckIssue
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "", strings.HasPrefix(line, "dangling "), strings.HasPrefix(line, "Checking "):
+			continue
+		case strings.HasPrefix(line, "warning"), strings.HasPrefix(line, "notice"):
+			issues = append(issues, fsckIssue{Severity: "warning", Message: line})
+		default:
+			// error:, fatal:, missing <type>, broken link, bad sha1, invalid sha1 pointer...
+			issues = append(issues, fsckIssue{Severity: "error", Message: line})
+		}
+	}
+	return issues
+}
+
+func hasErrors(issues []fsckIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == "error" {
+			return true
+		}
+	}
+	return false
+}
+
+// healthStatus is "unhealthy" with any error, "degraded" with only warnings, else "healthy"
+func healthStatus(issues []fsckIssue) string {
+	switch {
+	case hasErrors(issues):
+		return "unhealthy"
+	case len(issues) > 0:
+		return "degraded"
+	default:
+		return "healthy"
+	}
+}
+
+// checkRepoHealth runs git fsck on every repo for GET /health, saving what it can of unhealthy
+// repos to .git/lost-found with -auto-repair-on-fsck
+func checkRepoHealth(repos []string) {
+	for _, repo := range repos {
+		absPath, err := filepath.Abs(repo)
+		if err != nil {
+			continue
+		}
+		issues := runFsck(absPath)
+		status := healthStatus(issues)
+		if status != "healthy" {
+			fmt.Printf("  🩺 %s: %s, %d fsck issues (first: %s)\n", repo, status, len(issues), issues[0].Message)
+		}
+		if status == "unhealthy" && fsckRepair {
+			if output, err := gitIn(absPath, "fsck", "--no-progress", "--lost-found").CombinedOutput(); err != nil {
+				fmt.Printf("  ⚠️  %s: git fsck --lost-found: %s\n", repo, gitErrorLine(output, err))
+			} else {
+				fmt.Printf("  🩹 %s: Recoverable objects written to .git/lost-found\n", repo)
+			}
+		}
+		
+		state.update(absPath, func(repo *repoStatus) {
+			repo.FsckIssues = issues
+			repo.LastFsckAt = time.Now()
+		})
+	}
+}
+
+// healthHandler serves GET /health with the last fsck result of every repo. It answers 503
+// when any repo is unhealthy, so it can back a load balancer or container health check.
+func healthHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	
+	overall := "healthy"
+	repos := []repoHealth{}
+	for _, repo := range state.snapshot() {
+		if repo.LastFsckAt.IsZero() {
+			continue // Not checked yet
+		}
+		health := repoHealth{Path: repo.Repo, Status: healthStatus(repo.FsckIssues), CheckedAt: repo.LastFsckAt, Issues: repo.FsckIssues}
+		if health.Issues == nil {
+			health.Issues = []fsckIssue{}
+		}
+		if health.Status == "unhealthy" || health.Status == "degraded" && overall == "healthy" {
+			overall = health.Status
+		}
+		repos = append(repos, health)
+	}
+	
+	if overall == "unhealthy" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	writeJSON(w, map[string]interface{}{"status": overall, "repos": repos})
+}
\ No newline at end of file
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFsck(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []fsckIssue
+	}{
+		{"clean", "", nil},
+		{"dangling only", "Checking object directories\ndangling blob 3b18e512dba79e4c8300dd08aeb37f8e728b8dad\ndangling commit 8ab686eafeb1f44702738c8b0f24f2567c36da6d\n", nil},
+		{
+			name:   "missing object",
+			output: "broken link from    tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n              to    blob e69de29bb2d1d6434b8b29ae775ad8c2e48c5391\nmissing blob e69de29bb2d1d6434b8b29ae775ad8c2e48c5391\n",
+			want: []fsckIssue{
+				{"error", "broken link from    tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904"},
+				{"error", "to    blob e69de29bb2d1d6434b8b29ae775ad8c2e48c5391"},
+				{"error", "missing blob e69de29bb2d1d6434b8b29ae775ad8c2e48c5391"},
+			},
+		},
+		{
+			name:   "warnings",
+			output: "warning in tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904: zeroPaddedFilemode: contains zero-padded file modes\nnotice: HEAD points to an unborn branch (main)\n",
+			want: []fsckIssue{
+				{"warning", "warning in tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904: zeroPaddedFilemode: contains zero-padded file modes"},
+				{"warning", "notice: HEAD points to an unborn branch (main)"},
+			},
+		},
+		{"bad ref", "error: refs/heads/main: invalid sha1 pointer 0000000000000000000000000000000000000000\n", []fsckIssue{
+			{"error", "error: refs/heads/main: invalid sha1 pointer 0000000000000000000000000000000000000000"},
+		}},
+	}
+	for _, tt := range tests {
+		if got := parseFsck(tt.output); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: parseFsck = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestHealthStatus(t *testing.T) {
+	tests := []struct {
+		issues []fsckIssue
+		want   string
+	}{
+		{nil, "healthy"},
+		{[]fsckIssue{{"warning", "HEAD is detached"}}, "degraded"},
+		{[]fsckIssue{{"warning", "HEAD is detached"}, {"error", "missing blob e69de29"}}, "unhealthy"},
+	}
+	for _, tt := range tests {
+		if got := healthStatus(tt.issues); got != tt.want {
+			t.Errorf("healthStatus(%v) = %q, want %q", tt.issues, got, tt.want)
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,22 @@
+//go:build darwin
+
+package main
+
+import "syscall"
+
+// filesystemType names the filesystem path lives on, or "" if unknown
+func filesystemType(path string) string {
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(path, &fs); err != nil {
+		return ""
+	}
+	
+	name := make([]byte, 0, len(fs.Fstypename))
+	for _, c := range fs.Fstypename {
+		if c == 0 {
+			break
+		}
+		name = append(name, byte(c))
+	}
+	return string(name)
+}
\ No newline at end of file
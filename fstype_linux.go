@@ -0,0 +1,28 @@
+//go:build linux
+
+package main
+
+import "syscall"
+
+// linuxFilesystems maps statfs magic numbers to filesystem names
+var linuxFilesystems = map[int64]string{
+	0x9fa0:     "proc",
+	0x62656572: "sysfs",
+	0x794c7630: "overlay",
+	0x01021994: "tmpfs", // devtmpfs reports the tmpfs magic too
+	0x27e0eb:   "cgroup",
+	0x63677270: "cgroup2",
+	0x6969:     "nfs",
+	0xef53:     "ext4",
+	0x9123683e: "btrfs",
+	0x58465342: "xfs",
+}
+
+// filesystemType names the filesystem path lives on, or "" if unknown
+func filesystemType(path string) string {
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(path, &fs); err != nil {
+		return ""
+	}
+	return linuxFilesystems[int64(fs.Type)]
+}
\ No newline at end of file
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin
+
+package main
+
+// filesystemType is not implemented on this platform, so no filesystem is ever blocked
+func filesystemType(path string) string {
+	return ""
+}
\ No newline at end of file
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// githubAPIURL is the GitHub REST API root
+var githubAPIURL = "https://api.github.com"
+
+var errPullRequestExists = errors.New("a pull request already exists for this branch")
+
+// openedPRs remembers the repo branches a pull request was opened (or found) for
+var openedPRs = map[string]bool{}
+
+// githubRepoFromURL extracts owner and repo from a github.com remote URL in any of the
+// forms normalizeRemoteURL understands
+func githubRepoFromURL(url string) (string, string, bool) {
+	parts := strings.Split(normalizeRemoteURL(url), "/")
+	if len(parts) != 3 || parts[0] != "github.com" || parts[1] == "" || parts[2] == "" {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+// createPullRequest opens a pull request of head into base on github.com/owner/repo and returns its URL
+func createPullRequest(owner, repo, head, base, title, body string) (string, error) {
+	payload, err := json.Marshal(map[string]string{"title": title, "body": body, "head": head, "base": base})
+	if err != nil {
+		return "", err
+	}
+	
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/repos/%s/%s/pulls", githubAPIURL, owner, repo), bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+githubToken)
+	req.Header.Set("Content-Type", "application/json")
+	
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	
+	var result struct {
+		HTMLURL string `json:"html_url"`
+		Message string `json:"message"`
+		Errors  []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	if resp.StatusCode == http.StatusUnprocessableEntity {
+		for _, e := range result.Errors {
+			if strings.Contains(e.Message, "already exists") {
+				return "", errPullRequestExists
+			}
+		}
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("GitHub returned %s: %s", resp.Status, result.Message)
+	}
+	return result.HTMLURL, nil
+}
+
+// openPullRequest opens a pull request for the branch just pushed to the primary remote, once
+// per branch. It is used with -github-auto-pr so branches made by -auto-branch-on-protected get reviewed.
+func openPullRequest(repoPath, remote, branch string) {
+	key := repoPath + "\x00" + branch
+	if openedPRs[key] || matchesBranchPattern(branch, protectedBranches) {
+		return
+	}
+	owner, repo, ok := githubRepoFromURL(getRawRemotes()[remote])
+	if !ok {
+		return
+	}
+	
+	base := prBaseBranch
+	if base == "" {
+		var err error
+		if base, err = getRemoteDefaultBranch(remote); err != nil {
+			fmt.Printf("  ⚠️  No base branch for a pull request: %v\n", err)
+			return
+		}
+	}
+	if base == branch {
+		return
+	}
+	
+	data := commitTemplateData{
+		Timestamp: time.Now().Format("2006-01-02 15:04:05"),
+		Branch:    branch,
+		RepoName:  filepath.Base(repoPath),
+		Remote:    remote,
+	}
+	title, err := renderCommitMessage(prTitle, data)
+	if err != nil {
+		fmt.Printf("  ⚠️  Rendering -pr-title: %v\n", err)
+		return
+	}
+	body, err := renderCommitMessage(prBody, data)
+	if err != nil {
+		fmt.Printf("  ⚠️  Rendering -pr-body: %v\n", err)
+		return
+	}
+	
+	prURL, err := createPullRequest(owner, repo, branch, base, title, body)
+	if errors.Is(err, errPullRequestExists) {
+		openedPRs[key] = true
+		return
+	}
+	if err != nil {
+		fmt.Printf("  ⚠️  Opening pull request for %s: %v\n", branch, err)
+		reportError(filepath.Base(repoPath), "Opening pull request failed")
+		return
+	}
+	
+	openedPRs[key] = true
+	fmt.Printf("  🔀 Opened pull request %s\n", prURL)
+	// Repo state is keyed by the working directory processRepo changed into, not repoPath
+	state.update(getCurrentDir(), func(repo *repoStatus) { repo.PullRequestURL = prURL })
+}
\ No newline at end of file
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// gitAirIgnoreFile lists, in .gitignore syntax, changes git-air should never auto-commit in a repo
+const gitAirIgnoreFile = ".git-air-ignore"
+
+// loadGitAirIgnore reads the .git-air-ignore patterns of the repository at repoPath.
+// A missing file means no patterns. The file is re-read on every pass, so edits apply right away.
+func loadGitAirIgnore(repoPath string) ([]string, error) {
+	file, err := os.Open(filepath.Join(repoPath, gitAirIgnoreFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	
+	var patterns []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, scanner.Err()
+}
+
+// isGitAirIgnored applies .gitignore rules to a slash-separated repo path: the last matching
+// pattern wins and "!" patterns re-include what earlier ones excluded
+func isGitAirIgnored(path string, patterns []string) bool {
+	ignored := false
+	for _, pattern := range patterns {
+		negated := strings.HasPrefix(pattern, "!")
+		if matchIgnorePattern(strings.TrimPrefix(pattern, "!"), path) {
+			ignored = !negated
+		}
+	}
+	return ignored
+}
+
+// matchIgnorePattern matches one .gitignore pattern. Patterns containing a slash are anchored to
+// the repo root, others match a file or directory name at any depth; a trailing slash only
+// matches directories.
+func matchIgnorePattern(pattern, path string) bool {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	
+	segments := strings.Split(path, "/")
+	for i := range segments {
+		// Every leading directory, and the file itself unless the pattern wants a directory
+		if dirOnly && i == len(segments)-1 {
+			break
+		}
+		if anchored {
+			if matchPathPattern(pattern, strings.Join(segments[:i+1], "/")) {
+				return true
+			}
+		} else if matched, _ := filepath.Match(pattern, segments[i]); matched {
+			return true
+		}
+	}
+	return false
+}
\ No newline at end of file
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// initSetting is a per-repo setting "git-air init" asks for
+type initSetting struct {
+	key    string
+	prompt string
+	value  string
+}
+
+// initSettings lists the settings loadRepoConfig reads, with the values this instance would use
+func initSettings() []initSetting {
+	return []initSetting{
+		{"autoCommit", "Auto-commit changes (true/false)", "true"},
+		{"autoPush", "Push auto-commits to every remote (true/false)", "true"},
+		{"autoPull", "Pull remote changes every minute (true/false)", "true"},
+		{"debounceWindow", "Wait until changed files were left alone this long before committing", debounceWindow.String()},
+	}
+}
+
+// runInitCommand implements "git-air init [-non-interactive] [-force] [repo]", writing the per-repo
+// settings to the repo's git config
+func runInitCommand(args []string) {
+	initFlags := flag.NewFlagSet("init", flag.ExitOnError)
+	nonInteractive := initFlags.Bool("non-interactive", false, "Write the defaults without asking")
+	force := initFlags.Bool("force", false, "With -non-interactive, overwrite existing git-air settings")
+	initFlags.Parse(args)
+	
+	if repo := initFlags.Arg(0); repo != "" {
+		if err := os.Chdir(repo); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err := initRepoConfig(os.Stdin, os.Stdout, *nonInteractive, *force); err != nil {
+		log.Fatalf("git-air init: %v", err)
+	}
+}
+
+// initRepoConfig asks for each per-repo setting on in and out, or takes the defaults when
+// nonInteractive, and writes them to the current repo's git config. Existing git-air settings are
+// only overwritten after confirmation, or with force when nonInteractive.
+func initRepoConfig(in io.Reader, out io.Writer, nonInteractive, force bool) error {
+	if exec.Command("git", "rev-parse", "--git-dir").Run() != nil {
+		return errors.New("not inside a git repository")
+	}
+	
+	scanner := bufio.NewScanner(in)
+	ask := func(prompt, value string) string {
+		fmt.Fprintf(out, "%s [%s]: ", prompt, value)
+		if !scanner.Scan() {
+			return value
+		}
+		if answer := strings.TrimSpace(scanner.Text()); answer != "" {
+			return answer
+		}
+		return value
+	}
+	
+	existing, _ := exec.Command("git", "config", "--local", "--get-regexp", `^git-air\.`).Output()
+	if len(existing) > 0 {
+		switch {
+		case nonInteractive && !force:
+			return errors.New("the repository already has git-air settings, pass -force to overwrite them")
+		case !nonInteractive:
+			fmt.Fprintf(out, "The repository already has git-air settings:\n%s", existing)
+			var overwrite bool
+			if err := parseGitBool(ask("Overwrite them? (true/false)", "false"), &overwrite); err != nil || !overwrite {
+				return errors.New("settings left unchanged")
+			}
+		}
+	}
+	
+	settings := initSettings()
+	for i := range settings {
+		if nonInteractive {
+			break
+		}
+		// Ask until the answer parses the way loadRepoConfig reads it; at the end of the input the default is taken
+		for {
+			value := ask(settings[i].prompt, settings[i].value)
+			err := (&repoConfig{}).apply("git-air." + strings.ToLower(settings[i].key) + " " + value)
+			if err == nil {
+				settings[i].value = value
+				break
+			}
+			fmt.Fprintf(out, "  ⚠️  %v\n", err)
+		}
+	}
+	
+	for _, setting := range settings {
+		if output, err := exec.Command("git", "config", "--local", "git-air."+setting.key, setting.value).CombinedOutput(); err != nil {
+			return fmt.Errorf("git config git-air.%s: %s", setting.key, gitErrorLine(output, err))
+		}
+	}
+	fmt.Fprintf(out, "✅ Wrote git-air settings to the repository's git config (git config --get-regexp git-air)\n")
+	return nil
+}
\ No newline at end of file
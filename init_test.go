@@ -0,0 +1,51 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInitRepoConfig(t *testing.T) {
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(t.TempDir(), "gitconfig"))
+	dir := newTestRepo(t, filepath.Join(t.TempDir(), "repo"))
+	oldDir, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(oldDir)
+	oldWindow := debounceWindow
+	debounceWindow = 2 * time.Second
+	defer func() { debounceWindow = oldWindow }()
+	
+	// Non-interactive: the defaults, read back unchanged
+	if err := initRepoConfig(strings.NewReader(""), io.Discard, true, false); err != nil {
+		t.Fatal(err)
+	}
+	written, _ := gitIn(dir, "config", "--local", "--get-regexp", `^git-air\.`).Output()
+	want := "git-air.autocommit true\ngit-air.autopush true\ngit-air.autopull true\ngit-air.debouncewindow 2s\n"
+	if string(written) != want {
+		t.Errorf("written settings = %q, want %q", written, want)
+	}
+	if config, err := loadRepoConfig(); err != nil || config != (repoConfig{true, true, true, 2 * time.Second}) {
+		t.Errorf("loadRepoConfig() = %+v, %v, want the defaults", config, err)
+	}
+	
+	if err := initRepoConfig(strings.NewReader(""), io.Discard, true, false); err == nil {
+		t.Error("non-interactive init overwrote existing settings without -force")
+	}
+	
+	// Interactive: confirm the overwrite, keep autoCommit, retry an invalid autoPush answer
+	answers := "true\n\nsometimes\nfalse\nno\n30s\n"
+	if err := initRepoConfig(strings.NewReader(answers), io.Discard, false, false); err != nil {
+		t.Fatal(err)
+	}
+	if config, err := loadRepoConfig(); err != nil || config != (repoConfig{true, false, false, 30 * time.Second}) {
+		t.Errorf("loadRepoConfig() = %+v, %v, want the answers", config, err)
+	}
+	
+	if err := initRepoConfig(strings.NewReader("\n"), io.Discard, false, false); err == nil {
+		t.Error("interactive init overwrote existing settings without confirmation")
+	}
+}
\ No newline at end of file
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// leaderStaleAfter is how long a leader lock may go without a heartbeat before followers take over
+const leaderStaleAfter = 2 * time.Minute
+
+// leaderLock elects one of several git-air instances sharing repos (e.g. on NFS) to commit and push,
+// using a lock file created with O_EXCL. The leader refreshes the file's mtime as a heartbeat.
+type leaderLock struct {
+	path  string
+	owner string
+	mu    sync.Mutex
+	held  bool
+	stop  chan struct{}
+}
+
+// leader is nil unless -leader-lock is set
+var leader *leaderLock
+
+// newLeaderLock returns an unheld lock on path for this instance
+func newLeaderLock(path string) *leaderLock {
+	return &leaderLock{path: path, owner: lockOwner()}
+}
+
+// lockOwner is what the lock file contains: "<pid> <hostname> <nonce>", the nonce telling apart
+// instances that share a pid and host (e.g. in containers)
+func lockOwner() string {
+	hostname, _ := os.Hostname()
+	return strconv.Itoa(os.Getpid()) + " " + hostname + " " + strconv.FormatInt(time.Now().UnixNano(), 36)
+}
+
+// tryAcquire reports whether this instance is the leader, taking over the lock when it is free
+// or its holder is gone
+func (l *leaderLock) tryAcquire() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held {
+		if l.ownsLock() {
+			return true
+		}
+		l.drop()
+		return false
+	}
+	
+	if !l.create() {
+		if !l.isStale() || !l.takeOver() {
+			return false
+		}
+	}
+	
+	l.held = true
+	l.stop = make(chan struct{})
+	go l.heartbeat(l.stop)
+	fmt.Printf("👑 Leader: this instance commits and pushes (%s)\n", l.path)
+	return true
+}
+
+// create makes the lock file if it doesn't exist yet
+func (l *leaderLock) create() bool {
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+	fmt.Fprintln(file, l.owner)
+	return true
+}
+
+// takeOver replaces a stale lock file with ours in one rename, so the file is never missing, and
+// reports whether it still names us afterwards: when followers race, the last rename wins
+func (l *leaderLock) takeOver() bool {
+	tmp, err := os.CreateTemp(filepath.Dir(l.path), filepath.Base(l.path)+".*.tmp")
+	if err != nil {
+		return false
+	}
+	fmt.Fprintln(tmp, l.owner)
+	tmp.Close()
+	if err := os.Rename(tmp.Name(), l.path); err != nil {
+		os.Remove(tmp.Name())
+		return false
+	}
+	if !l.ownsLock() {
+		return false // Another follower was quicker
+	}
+	fmt.Println("👑 Leader lock was stale, took it over")
+	return true
+}
+
+// ownsLock reports whether the lock file names this instance
+func (l *leaderLock) ownsLock() bool {
+	data, err := os.ReadFile(l.path)
+	return err == nil && strings.TrimSpace(string(data)) == l.owner
+}
+
+// drop gives up leadership after another instance has taken the lock over; l.mu must be held
+func (l *leaderLock) drop() {
+	close(l.stop)
+	l.held = false
+	fmt.Printf("👑 Leader lock %s was taken over by another instance, following\n", l.path)
+}
+
+// isStale reports whether the lock holder is a dead process on this host or has stopped heartbeating
+func (l *leaderLock) isStale() bool {
+	info, err := os.Stat(l.path)
+	if err != nil {
+		return true
+	}
+	if time.Since(info.ModTime()) > leaderStaleAfter {
+		return true
+	}
+	
+	data, err := os.ReadFile(l.path)
+	if err != nil {
+		return false
+	}
+	fields := strings.Fields(string(data))
+	hostname, _ := os.Hostname()
+	if len(fields) >= 2 && fields[1] == hostname {
+		pid, _ := strconv.Atoi(fields[0])
+		return pid > 0 && !isProcessRunning(pid)
+	}
+	return false
+}
+
+// heartbeat touches the lock file until stop is closed, stepping down if it no longer names us
+func (l *leaderLock) heartbeat(stop chan struct{}) {
+	ticker := time.NewTicker(leaderStaleAfter / 4)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			l.mu.Lock()
+			select {
+			case <-stop:
+				l.mu.Unlock()
+				return
+			default:
+			}
+			if !l.ownsLock() {
+				l.drop()
+				l.mu.Unlock()
+				return
+			}
+			now := time.Now()
+			os.Chtimes(l.path, now, now)
+			l.mu.Unlock()
+		}
+	}
+}
+
+func (l *leaderLock) isHeld() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.held
+}
+
+// release gives up leadership, removing the lock file if it is still ours
+func (l *leaderLock) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.held {
+		return
+	}
+	
+	close(l.stop)
+	l.held = false
+	if l.ownsLock() {
+		os.Remove(l.path)
+	}
+}
+
+// isLeader reports whether this instance may commit and push: always without -leader-lock
+func isLeader() bool {
+	return leader == nil || leader.tryAcquire()
+}
\ No newline at end of file
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLeaderLockTwoContenders(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "leader.lock")
+	// A stale lock from an instance on another host that stopped heartbeating
+	if err := os.WriteFile(path, []byte("1 elsewhere x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * leaderStaleAfter)
+	os.Chtimes(path, old, old)
+	
+	for round := 0; round < 20; round++ {
+		a, b := newLeaderLock(path), newLeaderLock(path)
+		b.owner += "b" // Same process, possibly the same nanosecond
+		var wg sync.WaitGroup
+		for _, l := range []*leaderLock{a, b} {
+			wg.Add(1)
+			go func(l *leaderLock) {
+				defer wg.Done()
+				l.tryAcquire()
+			}(l)
+		}
+		wg.Wait()
+		
+		// A racing loser may briefly think it won; it must step down on its next check
+		leaders := 0
+		for _, l := range []*leaderLock{a, b} {
+			if l.tryAcquire() {
+				leaders++
+			}
+		}
+		if leaders != 1 {
+			t.Fatalf("round %d: %d leaders, want 1", round, leaders)
+		}
+		
+		a.release()
+		b.release()
+		if err := os.WriteFile(path, []byte("1 elsewhere x\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		os.Chtimes(path, old, old)
+	}
+}
+
+func TestLeaderLockStepsDownWhenTakenOver(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "leader.lock")
+	a := newLeaderLock(path)
+	if !a.tryAcquire() {
+		t.Fatal("first instance did not acquire a free lock")
+	}
+	defer a.release()
+	
+	b := newLeaderLock(path)
+	b.owner += "b" // Same process, possibly the same nanosecond
+	if b.tryAcquire() {
+		t.Fatal("second instance acquired a live lock")
+	}
+	
+	// Simulate b taking over after a's heartbeat was missed
+	if err := os.WriteFile(path, []byte(b.owner+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if a.tryAcquire() {
+		t.Error("first instance still leads after the lock was taken over")
+	}
+	if a.isHeld() {
+		t.Error("first instance still holds the lock after stepping down")
+	}
+}
\ No newline at end of file
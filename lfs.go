@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// prepareLFS makes sure changed files larger than -lfs-max-file-size-mb go through Git LFS,
+// tracking their extension when no .gitattributes pattern covers them yet.
+// Returns false when large files were found but git-lfs is not installed.
+func prepareLFS() bool {
+	if lfsMaxFileSizeMB <= 0 {
+		return true
+	}
+	
+	largeFiles := findLargeFiles(int64(lfsMaxFileSizeMB) << 20)
+	if len(largeFiles) == 0 {
+		return true
+	}
+	
+	if !isLFSInstalled() {
+		fmt.Printf("  ❌ Files over %d MB need Git LFS but git-lfs is not installed: %s\n", lfsMaxFileSizeMB, strings.Join(largeFiles, ", "))
+		return false
+	}
+	
+	patterns := getLFSTrackedPatterns()
+	for _, file := range largeFiles {
+		if matchesLFSPattern(file, patterns) {
+			continue
+		}
+		
+		pattern := lfsPatternFor(file)
+		if dryRun {
+			fmt.Printf("  [DRY-RUN] Would track %s with Git LFS\n", pattern)
+			continue
+		}
+		if !runGit("lfs", "track", pattern) {
+			fmt.Printf("  ❌ git lfs track %s failed\n", pattern)
+			return false
+		}
+		fmt.Printf("  📦 Tracking %s with Git LFS (%s is over %d MB)\n", pattern, file, lfsMaxFileSizeMB)
+		patterns = append(patterns, pattern)
+	}
+	return true
+}
+
+// findLargeFiles lists modified and untracked files bigger than limit bytes that stageChanges would stage
+func findLargeFiles(limit int64) []string {
+	ignore, _ := loadGitAirIgnore(".")
+	var large []string
+	for _, file := range changedFiles() {
+		if fileSize(file) > limit && isStageable(file, ignore) {
+			large = append(large, file)
+		}
+	}
+	return large
+}
+
+// getLFSTrackedPatterns returns the .gitattributes patterns that use the lfs filter
+func getLFSTrackedPatterns() []string {
+	data, err := os.ReadFile(".gitattributes")
+	if err != nil {
+		return nil
+	}
+	
+	var patterns []string
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		for _, attr := range fields[1:] {
+			if attr == "filter=lfs" {
+				patterns = append(patterns, fields[0])
+				break
+			}
+		}
+	}
+	return patterns
+}
+
+// matchesLFSPattern follows gitattributes rules: patterns without a slash match the
+// file name at any depth, others match the path from the repo root
+func matchesLFSPattern(file string, patterns []string) bool {
+	file = filepath.ToSlash(file)
+	for _, pattern := range patterns {
+		if !strings.Contains(pattern, "/") {
+			if matched, _ := filepath.Match(pattern, filepath.Base(file)); matched {
+				return true
+			}
+			continue
+		}
+		if matchPathPattern(strings.TrimPrefix(pattern, "/"), file) {
+			return true
+		}
+	}
+	return false
+}
+
+// lfsPatternFor tracks files by extension, or by exact path when they have none
+func lfsPatternFor(file string) string {
+	if ext := filepath.Ext(file); ext != "" {
+		return "*" + ext
+	}
+	return "/" + filepath.ToSlash(file)
+}
+
+// isLFSInstalled checks that the git-lfs extension is available
+func isLFSInstalled() bool {
+	return exec.Command("git", "lfs", "version").Run() == nil
+}
\ No newline at end of file
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindLargeFilesFollowsStagingRules(t *testing.T) {
+	dir := newTestRepo(t, filepath.Join(t.TempDir(), "repo"))
+	oldDir, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(oldDir)
+	
+	large := strings.Repeat("x", 100)
+	os.MkdirAll("assets", 0755)
+	os.MkdirAll("build", 0755)
+	for _, name := range []string{"assets/video.mp4", "build/bundle.bin", "dump.bin"} {
+		os.WriteFile(name, []byte(large), 0644)
+	}
+	os.WriteFile("small.txt", []byte("x"), 0644)
+	os.WriteFile(gitAirIgnoreFile, []byte("build/\n"), 0644)
+	
+	tests := []struct {
+		name         string
+		includePaths []string
+		want         []string
+	}{
+		{"everything but .git-air-ignore matches", nil, []string{"assets/video.mp4", "dump.bin"}},
+		{"only -include-paths", []string{"assets"}, []string{"assets/video.mp4"}},
+		{"ignored include path", []string{"build/*"}, nil},
+	}
+	for _, tt := range tests {
+		includePaths = tt.includePaths
+		if got := findLargeFiles(10); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: findLargeFiles() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	includePaths = nil
+}
\ No newline at end of file
@@ -1,25 +1,447 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 )
 
+// Command line options
+var (
+	debounceWindow    time.Duration
+	pullStrategy      string
+	allowedBranches   []string
+	blockedBranches   []string
+	commitTemplate    string
+	tagEvery          int
+	tagPrefix         string
+	versionFile       string
+	stashBeforePull   bool
+	pushConcurrency   int
+	webhookURL        string
+	githubToken       string
+	githubAutoPR      bool
+	prTitle           string
+	prBody            string
+	prBaseBranch      string
+	webhookSecret     string
+	webhookReceiver   string
+	statusAddr        string
+	statusToken       string
+	dryRun            bool
+	gpgSign           bool
+	gpgSigningKey     string
+	preCommitHook     string
+	hookTimeout       time.Duration
+	includePaths      []string
+	protectedBranches []string
+	autoBranch        bool
+	autoBranchPrefix  string
+	restoreBranch     bool
+	pushRetries       int
+	maxConcurrentOps  int
+	pushRetryDelay    time.Duration
+	networkTimeout    time.Duration
+	branchCacheTTL    time.Duration
+	scanCacheTTL      time.Duration
+	offlineMode       bool
+	drainTimeout      time.Duration
+	pidFile           string
+	leaderLockFile    string
+	failOnExistingPID bool
+	scanInterval      time.Duration
+	fsckInterval      time.Duration
+	fsckRepair        bool
+	pullBeforePush    bool
+	maxAheadPush      int
+	maxUnpushed       int
+	commitOnClose     bool
+	pruneMerged       bool
+	resetOnConflict   bool
+	amendWindow       time.Duration
+	minCommitGap      time.Duration
+	autoSquash        bool
+	squashAfter       int
+	squashOnShutdown  bool
+	batchMode         bool
+	batchWindow       time.Duration
+	pauseOnDiverge    bool
+	maxScanDepth      int
+	scanExcludes      []string
+	inactiveAfter     time.Duration
+	humanCommitsOnly  bool
+	maxRepoSizeMB     int64
+	conventional      bool
+	allowDetached     bool
+	lfsMaxFileSizeMB  int
+	submoduleCommit   bool
+	autoInit          bool
+	autoInitTemplate  string
+	commitCron        string
+	quietHours        []quietHourRange
+	quietLocation     *time.Location
+	remoteSSHKeys     map[string]string
+	fileTypePrefixes  []fileTypePrefix
+	initialRemotes    []remoteSpec
+	mirrorRemotes     []string
+	mirrorBranches    []string
+	maxFileSize       int64
+	excludeSymlinks   bool
+	internalLinksOnly bool
+	metricsAddr       string
+	blockedFS         []string
+	sparsePaths       []string
+	diffStatInMessage bool
+	logStagedFiles    bool
+	commitAuthorName  string
+	commitAuthorEmail string
+	overrideWhenEmpty bool
+	allowInCI         bool
+)
+
+// syncMu serialises repo operations, which chdir into the repo, between the main loop and the
+// HTTP handlers. Code that doesn't hold it must not depend on the current directory: repo paths
+// are absolute and git runs with gitIn.
+var syncMu sync.Mutex
+
+// scanRoot is the absolute directory repositories are discovered in, fixed at startup
+var scanRoot string
+
+// protectedWarned remembers repos already warned about sitting on a protected branch
+var protectedWarned = map[string]bool{}
+
+// quietDeferred marks repos whose changes were held back during quiet hours
+var quietDeferred = map[string]bool{}
+
+// shuttingDown marks the final commit pass made by -commit-on-close
+var shuttingDown bool
+
+// inCI turns off auto-commit and push when git-air runs inside a CI job, unless -allow-in-ci is set
+var inCI bool
+
+// detachedWarned remembers repos already warned about a detached HEAD
+var detachedWarned = map[string]bool{}
+
+// autoCommitCounts tracks successful auto-commits per repository for checkpoint tagging
+var autoCommitCounts = map[string]int{}
+
+// lastAutoCommit is when each repository was last auto-committed, for -min-commit-gap
+var lastAutoCommit = map[string]time.Time{}
+
+// pullStrategies maps each supported pull strategy to its git pull flags
+var pullStrategies = map[string][]string{
+	"merge":   nil,
+	"rebase":  {"--rebase"},
+	"ff-only": {"--ff-only"},
+}
+
 func main() {
+	profile := flag.String("profile", "", "Take the options not given on the command line or as GIT_AIR_* variables from this [git-air-profile \"<name>\"] git config section")
+	flag.DurationVar(&debounceWindow, "debounce-window", 2*time.Second, "Commit a repo only once its changed files have been left alone this long, so a burst of saves makes one commit (0 disables)")
+	flag.StringVar(&pullStrategy, "pull-strategy", "merge", "How to pull remote changes: merge, rebase or ff-only")
+	allowFlag := flag.String("allow-branches", "", "Comma-separated branch patterns to sync, e.g. \"main,release/*\" (default all)")
+	blockFlag := flag.String("block-branches", "", "Comma-separated branch patterns to never sync")
+	protectedFlag := flag.String("protected-branches", "main,master,release/*", "Comma-separated branch patterns that are never auto-committed or pushed (\"\" to disable)")
+	flag.BoolVar(&autoBranch, "auto-branch-on-protected", false, "Commit to a new timestamped branch instead of skipping protected branches")
+	flag.StringVar(&autoBranchPrefix, "auto-branch-prefix", "air/", "Prefix for branches created by -auto-branch-on-protected")
+	flag.BoolVar(&allowDetached, "allow-detached-head", false, "Commit changes made on a detached HEAD to a new air/detached-<sha> branch instead of skipping")
+	flag.BoolVar(&restoreBranch, "restore-after-auto-branch", false, "Switch back to the protected branch after committing to an auto-branch")
+	includeFlag := flag.String("include-paths", "", "Comma-separated glob patterns to stage instead of everything, e.g. \"src,docs/*.md\"")
+	flag.StringVar(&commitCron, "commit-cron", "", "Cron expression for auto-commit passes, e.g. \"0 17 * * 1-5\" (default every 30s)")
+	quietFlag := flag.String("quiet-hours", "", "Comma-separated HH:MM-HH:MM windows with no commits or pushes, e.g. \"22:00-07:00\"")
+	quietZoneFlag := flag.String("quiet-hours-timezone", "Local", "Time zone for -quiet-hours, e.g. Europe/Berlin")
+	flag.StringVar(&commitTemplate, "commit-template", "", "Commit message template using {{.Timestamp}}, {{.Branch}}, {{.FilesChanged}}, {{.RepoName}}, {{.Remote}} and {{.DiffStat.Insertions}}/{{.DiffStat.Deletions}}/{{.DiffStat.Files}}")
+	flag.BoolVar(&conventional, "conventional-commits", false, "Write Conventional Commits messages such as \"docs(auto): update README.md - <time>\"")
+	flag.Int64Var(&maxFileSize, "max-file-size-bytes", 0, "Leave changed files larger than this unstaged (0 disables)")
+	flag.BoolVar(&excludeSymlinks, "exclude-symlinks", false, "Never stage symbolic links")
+	flag.BoolVar(&internalLinksOnly, "exclude-external-symlinks", false, "Only stage symbolic links to relative paths inside the repo")
+	flag.IntVar(&lfsMaxFileSizeMB, "lfs-max-file-size-mb", 0, "Send changed files larger than this many MB through Git LFS, which must be installed (0 disables)")
+	flag.BoolVar(&submoduleCommit, "submodule-auto-commit", true, "Commit and push changes inside submodules (deepest first) before updating the parent")
+	flag.BoolVar(&diffStatInMessage, "diffstat-in-message", false, "Append \"(+insertions/-deletions in N files)\" to commit messages")
+	flag.BoolVar(&logStagedFiles, "log-staged-files", false, "Print each staged file with its status and line counts before committing")
+	flag.IntVar(&tagEvery, "tag-every", 0, "Create a checkpoint tag after every N auto-commits (0 disables)")
+	flag.StringVar(&tagPrefix, "tag-prefix", "air-checkpoint", "Prefix for checkpoint tag names")
+	flag.StringVar(&versionFile, "version-file", "", "Tag and push v<version> when a commit changes this file (VERSION, package.json or pyproject.toml, relative to the repo)")
+	flag.BoolVar(&stashBeforePull, "stash-before-pull", true, "Stash uncommitted changes before pulling and restore them afterwards")
+	flag.IntVar(&pushRetries, "push-retry-attempts", 3, "Attempts per remote before a push is given up")
+	flag.DurationVar(&pushRetryDelay, "push-retry-base-delay", 5*time.Second, "Delay before the first push retry, doubled after each failure")
+	flag.DurationVar(&branchCacheTTL, "branch-cache-ttl", time.Hour, "How long a remote's default branch is remembered before asking the remote again")
+	flag.DurationVar(&scanCacheTTL, "scan-cache-ttl", 10*time.Minute, "Reuse a repo's monorepo analysis while its .git is unchanged, for at most this long (0 = always re-analyze)")
+	flag.DurationVar(&networkTimeout, "network-timeout", 30*time.Second, "Timeout for git network operations: reachability checks, fetch, pull and push")
+	flag.BoolVar(&offlineMode, "offline-mode", false, "Skip remote reachability checks (air-gapped setups)")
+	flag.BoolVar(&pruneMerged, "prune-merged-branches", false, "Delete local branches whose remote branch is gone once they are merged")
+	flag.BoolVar(&resetOnConflict, "hard-reset-on-conflict", false, "When a pull fails, stash local changes and hard-reset to the remote branch (destructive: unpushed commits leave the branch)")
+	flag.BoolVar(&pullBeforePush, "pull-before-push", false, "Pull first when the branch is behind its remote")
+	flag.IntVar(&maxAheadPush, "max-ahead-before-push", 0, "Don't push when more than N commits ahead of the remote (0 = unlimited)")
+	flag.IntVar(&maxUnpushed, "max-unpushed-commits", 50, "Warn when more than N commits have not reached the remote (0 = never)")
+	flag.BoolVar(&pauseOnDiverge, "pause-on-divergence", true, "Stop auto operations on a repo while its branch has diverged from the remote")
+	fileTypesFlag := flag.String("file-type-prefixes", "", "Comma-separated pattern=prefix pairs committing each file type separately, e.g. \"*.go=refactor,*.md=docs,*.yaml=config\"")
+	sshKeysFlag := flag.String("remote-ssh-keys", "", "Comma-separated remote=keyfile pairs, e.g. \"origin=~/.ssh/id_github,gitlab=~/.ssh/id_gitlab\"")
+	remotesFlag := flag.String("initial-remotes", "", "Comma-separated name=url remotes to add to every repo that lacks them")
+	mirrorFlag := flag.String("mirror-remotes", "", "Comma-separated remotes that get git push --mirror after a successful normal push")
+	mirrorBranchesFlag := flag.String("mirror-only-branches", "", "Comma-separated branch patterns that trigger mirroring (default all)")
+	flag.IntVar(&pushConcurrency, "push-concurrency", 3, "Maximum number of remotes to push to in parallel")
+	flag.IntVar(&maxConcurrentOps, "max-concurrent-ops", 8, "Maximum number of network git operations (push, fetch, pull, ls-remote) running at once across all repos")
+	flag.StringVar(&webhookURL, "webhook-url", "", "URL to POST commit and push events to")
+	flag.StringVar(&githubToken, "github-token", os.Getenv("GITHUB_TOKEN"), "GitHub API token for -github-auto-pr (default $GITHUB_TOKEN)")
+	flag.BoolVar(&githubAutoPR, "github-auto-pr", false, "Open a GitHub pull request after pushing a non-protected branch to a github.com remote")
+	flag.StringVar(&prTitle, "pr-title", "git-air: {{.Branch}}", "Pull request title template (same fields as -commit-template)")
+	flag.StringVar(&prBody, "pr-body", "Changes auto-committed by git-air in {{.RepoName}}.", "Pull request body template")
+	flag.StringVar(&prBaseBranch, "pr-base-branch", "", "Branch pull requests target (default the remote's default branch)")
+	flag.StringVar(&webhookSecret, "webhook-secret", "", "Secret used to sign webhook payloads (X-Git-Air-Signature) and to verify events sent to -webhook-receiver")
+	flag.StringVar(&webhookReceiver, "webhook-receiver", "", "Accept GitHub, GitLab and Gitea push events at POST /webhook/sync on this address, e.g. :9091 (requires -webhook-secret)")
+	flag.BoolVar(&dryRun, "dry-run", false, "Show what would be committed, pushed and pulled without doing it")
+	flag.StringVar(&commitAuthorName, "commit-author-name", "git-air[bot]", "Author name for auto-commits (\"\" keeps the git config identity)")
+	flag.StringVar(&commitAuthorEmail, "commit-author-email", "git-air@localhost", "Author email for auto-commits (\"\" keeps the git config identity)")
+	flag.BoolVar(&overrideWhenEmpty, "override-author-when-empty", false, "Only use -commit-author-name/-email in repos without a configured user identity")
+	flag.BoolVar(&gpgSign, "gpg-sign", false, "GPG-sign auto-commits")
+	flag.StringVar(&gpgSigningKey, "gpg-signing-key", "", "Key fingerprint to sign with (default user.signingkey)")
+	flag.StringVar(&preCommitHook, "pre-commit-hook", "", "Executable to run before each auto-commit; a non-zero exit skips the commit")
+	flag.DurationVar(&hookTimeout, "pre-commit-hook-timeout", 30*time.Second, "Maximum time the pre-commit hook may run")
+	flag.DurationVar(&amendWindow, "amend-window", 0, "Amend the previous auto-commit instead of adding one when it is younger than this and not pushed yet (0 = never)")
+	flag.DurationVar(&minCommitGap, "min-commit-gap", 6*time.Second, "Minimum time between two auto-commits of the same repo, so a burst of cron passes, forced syncs and webhooks makes one commit (0 = no limit)")
+	flag.BoolVar(&autoSquash, "auto-squash", false, "Squash unpushed auto-commits into one before pushing")
+	flag.IntVar(&squashAfter, "squash-after", 5, "With -auto-squash, squash once more than this many auto-commits are waiting to be pushed")
+	flag.BoolVar(&squashOnShutdown, "squash-on-shutdown", false, "Squash every unpushed auto-commit, including the shutdown commit, before the final push")
+	flag.BoolVar(&batchMode, "batch-mode", false, "Collect changes across all repos for -batch-window, then commit them together in dependency order and push only if every commit succeeds")
+	flag.DurationVar(&batchWindow, "batch-window", time.Minute, "How long -batch-mode waits after the first change before committing the batch")
+	flag.BoolVar(&allowInCI, "allow-in-ci", false, "Auto-commit and push even when a CI environment variable such as CI or GITHUB_ACTIONS is set")
+	ciVarsFlag := flag.String("ci-env-vars", "", "Comma-separated extra environment variables that mark a CI environment")
+	flag.BoolVar(&commitOnClose, "commit-on-close", true, "Commit and push remaining changes when shutting down")
+	flag.DurationVar(&drainTimeout, "drain-timeout", 30*time.Second, "How long to wait for in-progress operations on shutdown")
+	flag.StringVar(&leaderLockFile, "leader-lock", "", "Shared lock file electing one of several instances to commit and push; the others only pull")
+	flag.StringVar(&pidFile, "pid-file", ".git/git-air.pid", "PID file written inside each repo to stop two git-air instances managing it (\"\" to disable)")
+	flag.BoolVar(&failOnExistingPID, "fail-on-existing-pid", false, "Exit instead of skipping repos already managed by another git-air")
+	flag.DurationVar(&scanInterval, "scan-interval", 5*time.Minute, "How often to look for added and removed repositories (at least 30s)")
+	flag.DurationVar(&fsckInterval, "fsck-interval", 24*time.Hour, "How often to check every repo with git fsck for GET /health (0 = never)")
+	flag.BoolVar(&fsckRepair, "auto-repair-on-fsck", false, "Run git fsck --lost-found on repos fsck finds errors in, saving recoverable objects to .git/lost-found")
+	flag.IntVar(&maxScanDepth, "max-scan-depth", 5, "How many directory levels below the start directory to search for repos (0 = unlimited)")
+	sparseFlag := flag.String("sparse-checkout-paths", "", "Comma-separated directories to check out (cone-mode sparse checkout), for large monorepos")
+	blockedFSFlag := flag.String("blocked-filesystems", "proc,sysfs,overlay,tmpfs,devtmpfs", "Comma-separated filesystem types the repo scan never descends into")
+	excludeFlag := flag.String("scan-exclude", "", "Comma-separated path patterns to skip while scanning, e.g. \"**/build/**,archive/*\"")
+	flag.DurationVar(&inactiveAfter, "inactive-threshold", 0, "Skip repos whose last commit is older than this, e.g. 720h (0 disables)")
+	flag.BoolVar(&humanCommitsOnly, "inactive-if-no-human-commits", false, "With -inactive-threshold, ignore git-air's own commits so repos only git-air commits to go inactive")
+	flag.Int64Var(&maxRepoSizeMB, "max-repo-size-mb", 0, "Skip repos whose .git directory is larger than this many MB (0 disables)")
+	flag.BoolVar(&autoInit, "auto-init", false, "Run git init in project directories (go.mod, package.json, ...) that aren't repos yet")
+	flag.StringVar(&autoInitTemplate, "auto-init-template", "", "Template directory passed to git init --template for -auto-init")
+	flag.StringVar(&metricsAddr, "metrics-addr", "", "Serve Prometheus metrics at GET /metrics on this address, e.g. :9090")
+	pauseFor := flag.Duration("pause-for", 0, "Pause the git-air instance serving -status-addr for this long, e.g. 15m, and exit")
+	forceReset := flag.Bool("force-reset", false, "Stash local changes, hard-reset the repo in the current directory (or the one given) to its remote branch, and exit")
+	showStats := flag.Bool("stats", false, "Print the sync statistics of the git-air instance serving -status-addr and exit")
+	flag.StringVar(&statusAddr, "status-addr", "", "Serve the JSON status API on this address, e.g. localhost:8080")
+	flag.StringVar(&statusToken, "status-token", "", "Bearer token the status API requires for requests that change state; without it those are only accepted from localhost")
+	slackURL := flag.String("slack-webhook-url", "", "Slack incoming webhook URL for notifications")
+	slackChannel := flag.String("slack-channel", "", "Slack channel override, e.g. #dev-sync")
+	slackOnCommit := flag.Bool("slack-on-commit", true, "Notify Slack on auto-commits and pushes")
+	slackOnError := flag.Bool("slack-on-error", true, "Notify Slack when sync operations fail")
+	auditFile := flag.String("audit-log", "", "Append a JSON line for every auto-commit and push to this file")
+	logEvents := flag.Bool("log-events", false, "Log a key=value line for every commit, push, pull and error")
+	auditMaxSizeMB := flag.Int64("audit-log-max-size-mb", 100, "Rotate the audit log to <file>.1 once it reaches this size (0 = never)")
+	flag.Parse()
+	if err := applyEnvOverrides(flag.CommandLine); err != nil {
+		log.Fatalf("Invalid environment: %v", err)
+	}
+	if *profile != "" {
+		if err := applyProfile(flag.CommandLine, *profile); err != nil {
+			log.Fatalf("Invalid -profile: %v", err)
+		}
+	}
+	
+	if *slackURL != "" {
+		slack = &slackNotifier{
+			webhookURL: *slackURL,
+			channel:    *slackChannel,
+			onCommit:   *slackOnCommit,
+			onError:    *slackOnError,
+		}
+	}
+	if *auditFile != "" {
+		path, err := filepath.Abs(*auditFile)
+		if err != nil {
+			log.Fatalf("Invalid -audit-log: %v", err)
+		}
+		audit = &auditLogger{path: path, maxSize: *auditMaxSizeMB * 1024 * 1024}
+	}
+	if *logEvents {
+		addHandler(logHandler{})
+	}
+	
+	allowedBranches = splitList(*allowFlag)
+	blockedBranches = splitList(*blockFlag)
+	includePaths = splitList(*includeFlag)
+	protectedBranches = splitList(*protectedFlag)
+	scanExcludes = splitList(*excludeFlag)
+	blockedFS = splitList(*blockedFSFlag)
+	sparsePaths = splitList(*sparseFlag)
+	mirrorRemotes = splitList(*mirrorFlag)
+	mirrorBranches = splitList(*mirrorBranchesFlag)
+	ciEnvVars = append(ciEnvVars, splitList(*ciVarsFlag)...)
+	
+	if *pauseFor > 0 {
+		if statusAddr == "" {
+			log.Fatal("-pause-for needs -status-addr of the running git-air instance")
+		}
+		requestPause(statusAddr, *pauseFor)
+		return
+	}
+	
+	if *forceReset {
+		runForceReset(flag.Arg(0))
+		return
+	}
+	
+	if *showStats {
+		if statusAddr == "" {
+			log.Fatal("-stats needs -status-addr of the running git-air instance")
+		}
+		printStats(statusAddr)
+		return
+	}
+	
+	// Subcommands run once and exit instead of starting the daemon
+	switch flag.Arg(0) {
+	case "init":
+		runInitCommand(flag.Args()[1:])
+		return
+	case "log":
+		runLogCommand(flag.Args()[1:])
+		return
+	case "undo-last":
+		runUndoCommand(flag.Args()[1:])
+		return
+	case "force-sync":
+		runForceSyncCommand(flag.Args()[1:])
+		return
+	case "status":
+		runStatusCommand(flag.Args()[1:])
+		return
+	case "branches":
+		runBranchesCommand(flag.Args()[1:])
+		return
+	}
+	
+	if _, ok := pullStrategies[pullStrategy]; !ok {
+		log.Fatalf("Unknown pull strategy %q (use merge, rebase or ff-only)", pullStrategy)
+	}
+	errs := validateFlags()
+	if *auditMaxSizeMB < 0 {
+		errs = append(errs, fmt.Errorf("-audit-log-max-size-mb must be at least 0, got %d", *auditMaxSizeMB))
+	}
+	if len(errs) > 0 {
+		log.Fatalf("Invalid options:\n%v", errors.Join(errs...))
+	}
+	
+	schedule, err := newCommitSchedule(commitCron, 30*time.Second)
+	if err != nil {
+		log.Fatalf("Invalid -commit-cron: %v", err)
+	}
+	if schedule.next(time.Now()).IsZero() {
+		log.Fatalf("-commit-cron %q never fires", commitCron)
+	}
+	
+	if quietHours, err = parseQuietHours(*quietFlag); err != nil {
+		log.Fatalf("Invalid -quiet-hours: %v", err)
+	}
+	if quietLocation, err = time.LoadLocation(*quietZoneFlag); err != nil {
+		log.Fatalf("Invalid -quiet-hours-timezone: %v", err)
+	}
+	
+	if remoteSSHKeys, err = parseRemoteSSHKeys(*sshKeysFlag); err != nil {
+		log.Fatalf("Invalid -remote-ssh-keys: %v", err)
+	}
+	if fileTypePrefixes, err = parseFileTypePrefixes(*fileTypesFlag); err != nil {
+		log.Fatalf("Invalid -file-type-prefixes: %v", err)
+	}
+	
+	if initialRemotes, err = parseRemoteSpecs(*remotesFlag); err != nil {
+		log.Fatalf("Invalid -initial-remotes: %v", err)
+	}
+	for _, spec := range initialRemotes {
+		if err := validateRemoteURL(spec.URL); err != nil {
+			log.Fatalf("Invalid -initial-remotes: %v", err)
+		}
+	}
+	
+	if githubAutoPR && githubToken == "" {
+		log.Fatal("-github-auto-pr needs -github-token or $GITHUB_TOKEN")
+	}
+	
+	opSlots = make(chan struct{}, maxConcurrentOps)
+	
+	if gpgSign && !isGPGAvailable() {
+		log.Fatal("-gpg-sign is set but the gpg binary was not found in PATH")
+	}
+	
+	// Hooks run from inside each repo, so resolve relative paths now
+	if preCommitHook != "" {
+		hookPath, err := filepath.Abs(preCommitHook)
+		if err != nil {
+			log.Fatalf("Invalid pre-commit hook: %v", err)
+		}
+		preCommitHook = hookPath
+	}
+	
+	// Catch template mistakes now rather than on the first commit
+	if commitTemplate != "" {
+		if _, err := renderCommitMessage(commitTemplate, commitTemplateData{}); err != nil {
+			log.Fatalf("Invalid commit template: %v", err)
+		}
+	}
+	
 	fmt.Println("🚀 Git Air - Auto sync all Git repos")
 	fmt.Println("📡 Inter-project communication via Git synchronization")
 	fmt.Println("📚 Supports monorepos and multi-repos")
 	
+	if statusAddr != "" {
+		startStatusServer(statusAddr)
+	}
+	if metricsAddr != "" {
+		startMetricsServer(metricsAddr)
+	}
+	if webhookReceiver != "" {
+		if webhookSecret == "" {
+			log.Fatal("-webhook-receiver needs -webhook-secret so forged push events are rejected")
+		}
+		startWebhookReceiver(webhookReceiver)
+	}
+	
 	// Find all git repos in current directory and subdirs
-	repos, err := findGitRepos(".")
+	if scanRoot, err = filepath.Abs("."); err != nil {
+		log.Fatal(err)
+	}
+	repos, err := findGitRepos(scanRoot)
 	if err != nil {
 		log.Fatal(err)
 	}
+	// Commit nested repos and submodules before the repos containing them
+	repos = orderRepos(repos)
+	
+	if dryRun {
+		fmt.Println("[DRY-RUN] No commits, pushes or pulls will be made")
+	}
+	
+	if len(protectedBranches) > 0 {
+		fmt.Printf("🛡️  Protected branches (never auto-committed): %s\n", strings.Join(protectedBranches, ", "))
+	}
+	
+	// Don't race another git-air instance on the same repos
+	repos = acquirePIDFiles(repos)
+	defer func() { releasePIDFiles(repos) }()
+	ensureInitialRemotes(repos)
+	ensureSparseCheckout(repos)
+	checkRepoSizes(repos)
 	
 	fmt.Printf("Found %d Git repositories\n", len(repos))
 	for _, repo := range repos {
@@ -27,33 +449,218 @@ func main() {
 		if isMonorepo(repo) {
 			repoType = "MONOREPO"
 		}
-		fmt.Printf("  📁 %s [%s]\n", repo, repoType)
+		fmt.Printf("  📁 %s [%s] 👥 %d active contributors\n", repo, repoType, countActiveContributors(repo))
+	}
+	// Repos without a remote are only committed locally, which is easy to miss
+	var orphans []string
+	for _, repo := range repos {
+		if output, err := gitIn(repo, "remote").Output(); err == nil && strings.TrimSpace(string(output)) == "" {
+			orphans = append(orphans, repo)
+		}
+	}
+	if len(orphans) > 0 {
+		fmt.Printf("🏝️  No remote configured (commits stay local): %s\n", strings.Join(orphans, ", "))
+	}
+	
+	// Stop between operations on Ctrl+C / SIGTERM instead of mid-push
+	shutdown := handleShutdown()
+	
+	// Without -commit-cron the first pass runs straight away
+	nextCommit := time.Now()
+	if commitCron != "" {
+		nextCommit = schedule.next(time.Now())
+		fmt.Printf("⏰ Auto-commit schedule %q, next pass at %s\n", commitCron, nextCommit.Format("2006-01-02 15:04"))
+	}
+	
+	// Committing on top of a CI checkout would push build state back to the repos
+	if isRunningInCI() && !allowInCI {
+		fmt.Printf("🤖 CI environment detected ($%s), disabling auto-commit and auto-push (-allow-in-ci to override)\n", ciMarker())
+		inCI = true
+	}
+	
+	if leaderLockFile != "" {
+		path, err := filepath.Abs(leaderLockFile)
+		if err != nil {
+			log.Fatalf("Invalid -leader-lock: %v", err)
+		}
+		leader = newLeaderLock(path)
+		defer leader.release()
 	}
 	
-	// Main loop - check every 30 seconds for changes, pull every minute
+	// Main loop - commit on schedule (every 30 seconds by default), pull every minute
 	lastPull := time.Now()
+	lastScan := time.Now()
+	var lastFsck time.Time
+	following := false
 	for {
+		// Pick up repos cloned or deleted since startup
+		if time.Since(lastScan) >= scanInterval {
+			repos = rescanRepos(repos)
+			lastScan = time.Now()
+		}
+		
+		// Followers leave committing and pushing to the -leader-lock holder
+		leading := isLeader()
+		if !leading && !following {
+			fmt.Printf("👥 Follower: another instance holds %s, only pulling\n", leaderLockFile)
+		}
+		following = !leading
+		
 		// Auto commit and push changes
-		for _, repo := range repos {
-			processRepo(repo)
+		paused := pause.isPaused()
+		if !paused && leading && !inCI && !time.Now().Before(nextCommit) {
+			if batchMode {
+				if batchDue(repos) {
+					if err := runBatch(repos); err != nil {
+						fmt.Printf("  ❌ Batch failed: %v\n", err)
+					}
+				}
+			} else {
+				for _, repo := range repos {
+					if isClosed(shutdown) {
+						commitOnShutdown(repos)
+						return
+					}
+					processRepo(repo)
+				}
+			}
+			nextCommit = schedule.next(time.Now())
 		}
 		
 		// Pull from all repos every minute for inter-project communication
-		if time.Since(lastPull) >= time.Minute {
+		if !paused && time.Since(lastPull) >= time.Minute {
 			fmt.Println("\n📡 Checking for inter-project updates...")
 			for _, repo := range repos {
+				if isClosed(shutdown) {
+					commitOnShutdown(repos)
+					return
+				}
 				pullUpdates(repo)
 			}
 			lastPull = time.Now()
 		}
 		
-		time.Sleep(30 * time.Second)
+		// Look for corrupt objects and broken refs once per -fsck-interval, starting with the first pass
+		if fsckInterval > 0 && time.Since(lastFsck) >= fsckInterval {
+			checkRepoHealth(repos)
+			lastFsck = time.Now()
+		}
+		
+		wait := 30 * time.Second
+		if untilCommit := time.Until(nextCommit); untilCommit > 0 && untilCommit < wait {
+			wait = untilCommit
+		}
+		select {
+		case <-shutdown:
+			commitOnShutdown(repos)
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// commitOnShutdown makes a last commit and push of every repo with -commit-on-close, so
+// changes made just before Ctrl+C aren't left behind. -drain-timeout still bounds how long it takes.
+func commitOnShutdown(repos []string) {
+	if !commitOnClose || inCI || pause.isPaused() || (leader != nil && !leader.isHeld()) {
+		return
+	}
+	
+	fmt.Println("💾 Committing remaining changes before exit...")
+	shuttingDown = true
+	for _, repo := range repos {
+		processRepo(repo)
+	}
+}
+
+// rescanRepos rediscovers repositories, starting on new ones and dropping deleted ones
+func rescanRepos(current []string) []string {
+	found, err := findGitRepos(scanRoot)
+	if err != nil {
+		fmt.Printf("⚠️  Repository scan failed: %v\n", err)
+		return current
+	}
+	
+	known := map[string]bool{}
+	for _, repo := range current {
+		known[repo] = true
+	}
+	
+	var added []string
+	seen := map[string]bool{}
+	for _, repo := range found {
+		seen[repo] = true
+		if !known[repo] {
+			added = append(added, repo)
+		}
+	}
+	added = acquirePIDFiles(added)
+	ensureInitialRemotes(added)
+	ensureSparseCheckout(added)
+	checkRepoSizes(added)
+	
+	var repos []string
+	removed := 0
+	for _, repo := range current {
+		if !seen[repo] {
+			fmt.Printf("  ➖ Repository removed: %s\n", repo)
+			if absPath, err := filepath.Abs(repo); err == nil {
+				state.forget(absPath)
+				forgetRepoInfo(absPath)
+			}
+			removed++
+			continue
+		}
+		repos = append(repos, repo)
+	}
+	for _, repo := range added {
+		fmt.Printf("  ➕ New repository: %s\n", repo)
+		repos = append(repos, repo)
+	}
+	
+	state.countScanChanges(len(added), removed)
+	return orderRepos(repos)
+}
+
+// handleShutdown returns a channel that is closed on SIGINT or SIGTERM.
+// If in-progress operations don't finish within -drain-timeout, or a second
+// signal arrives, git-air exits immediately.
+func handleShutdown() <-chan struct{} {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	
+	shutdown := make(chan struct{})
+	go func() {
+		<-signals
+		fmt.Println("\n🛑 Shutting down - waiting for in-progress operations to finish...")
+		close(shutdown)
+		
+		select {
+		case <-signals:
+			fmt.Println("❌ Second signal received, exiting immediately")
+		case <-time.After(drainTimeout):
+			fmt.Printf("❌ Operations did not finish within %s, exiting\n", drainTimeout)
+		}
+		os.Exit(1)
+	}()
+	return shutdown
+}
+
+// isClosed reports whether ch has been closed, without blocking
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
 	}
 }
 
 // findGitRepos finds all .git directories
+// The paths returned are absolute when root is.
 func findGitRepos(root string) ([]string, error) {
 	var repos []string
+	rootFS := filesystemType(root)
 	
 	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -72,129 +679,1385 @@ func findGitRepos(root string) ([]string, error) {
 			return filepath.SkipDir // Don't go into .git
 		}
 		
+		if !info.IsDir() {
+			return nil
+		}
+		
+		// Turn new projects into repos when -auto-init is on
+		if autoInit && isUninitializedProject(path) {
+			if initRepo(path) {
+				repos = append(repos, path)
+			}
+			return filepath.SkipDir
+		}
+		
+		if path == root {
+			return nil
+		}
+		
+		// Stop at -max-scan-depth and skip -scan-exclude matches
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		if maxScanDepth > 0 && strings.Count(rel, string(os.PathSeparator)) >= maxScanDepth {
+			return filepath.SkipDir
+		}
+		for _, pattern := range scanExcludes {
+			if matchPathPattern(pattern, filepath.ToSlash(rel)) {
+				return filepath.SkipDir
+			}
+		}
+		
+		// Stay out of /proc, container overlays and the like, unless the scan started on one
+		if fsType := filesystemType(path); fsType != rootFS && isBlockedFilesystem(fsType, blockedFS) {
+			return filepath.SkipDir
+		}
+		
 		return nil
 	})
 	
 	return repos, err
 }
 
+// isBlockedFilesystem reports whether fsType is one of the -blocked-filesystems
+func isBlockedFilesystem(fsType string, blocked []string) bool {
+	if fsType == "" {
+		return false
+	}
+	for _, name := range blocked {
+		if name == fsType {
+			return true
+		}
+	}
+	return false
+}
+
+// projectFiles mark a directory as a project worth putting under version control
+var projectFiles = []string{"go.mod", "package.json", "Cargo.toml", "requirements.txt", "pyproject.toml", "pom.xml"}
+
+// isUninitializedProject reports whether dir holds a project file but isn't part of any git work tree
+func isUninitializedProject(dir string) bool {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		return false
+	}
+	
+	found := false
+	for _, name := range projectFiles {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return false
+	}
+	
+	cmd := exec.Command("git", "rev-parse", "--is-inside-work-tree")
+	cmd.Dir = dir
+	return cmd.Run() != nil
+}
+
+// initRepo runs git init in dir and records an initial commit of its contents
+func initRepo(dir string) bool {
+	if dryRun {
+		fmt.Printf("  [DRY-RUN] Would git init new project %s\n", dir)
+		return false
+	}
+	
+	args := []string{"init", "-q"}
+	if autoInitTemplate != "" {
+		args = append(args, "--template="+autoInitTemplate)
+	}
+	
+	// Rescans run without syncMu, so stay out of the current directory
+	if gitIn(dir, args...).Run() != nil {
+		fmt.Printf("  ❌ git init failed in %s\n", dir)
+		return false
+	}
+	gitIn(dir, "add", ".").Run()
+	if gitIn(dir, commitArgs("initial commit (git-air)")...).Run() != nil {
+		fmt.Printf("  ⚠️  Initialized %s but the initial commit failed\n", dir)
+	}
+	fmt.Printf("  🌱 Initialized new repository %s\n", dir)
+	return true
+}
+
 // processRepo handles one git repository
 func processRepo(repoPath string) {
+	syncMu.Lock()
+	defer syncMu.Unlock()
+	
 	// Change to repo directory
 	oldDir, _ := os.Getwd()
 	os.Chdir(repoPath)
 	defer os.Chdir(oldDir)
+	defer refreshRepoState()
+	
+	config, err := loadRepoConfig()
+	if err != nil {
+		fmt.Printf("  ⚠️  %s: Ignoring invalid settings: %v\n", filepath.Base(repoPath), err)
+	}
+	if !config.autoCommit || checkInactive() || isTooLarge() {
+		return
+	}
+	
+	// Hold changes back until quiet hours are over
+	if isQuietHour(time.Now(), quietHours, quietLocation) {
+		if !quietDeferred[repoPath] && hasChanges() {
+			fmt.Printf("  🌙 %s: Quiet hours, holding changes until they end\n", filepath.Base(repoPath))
+			quietDeferred[repoPath] = true
+		}
+		return
+	}
+	
+	// A detached HEAD has no branch to push, so skip it or give the changes a branch
+	if detached, sha := isDetachedHead(); detached {
+		if !allowDetached {
+			if !detachedWarned[repoPath] {
+				fmt.Printf("  ⚠️  %s: Detached HEAD at %.7s, skipping auto-commit\n", filepath.Base(repoPath), sha)
+				detachedWarned[repoPath] = true
+			}
+			return
+		}
+		if !hasChanges() {
+			return
+		}
+		
+		newBranch := fmt.Sprintf("%sdetached-%.7s", autoBranchPrefix, sha)
+		if dryRun {
+			fmt.Printf("  [DRY-RUN] Would move changes from detached HEAD to new branch %s\n", newBranch)
+			return
+		}
+		if !createAndCheckoutBranch(newBranch) {
+			reportError(filepath.Base(repoPath), "Failed to create branch "+newBranch)
+			return
+		}
+		fmt.Printf("  🌿 %s: Moved changes from detached HEAD to new branch %s\n", filepath.Base(repoPath), newBranch)
+	}
+	detachedWarned[repoPath] = false
+	
+	// Leave branches excluded by -allow-branches / -block-branches alone
+	branch := getCurrentBranch()
+	if !isBranchAllowed(branch) {
+		return
+	}
+	
+	// Never auto-commit half-finished work onto a protected branch
+	if matchesBranchPattern(branch, protectedBranches) {
+		if !autoBranch {
+			if !protectedWarned[repoPath] {
+				fmt.Printf("  ⚠️  %s: On protected branch %s, skipping auto-commit\n", filepath.Base(repoPath), branch)
+				protectedWarned[repoPath] = true
+			}
+			return
+		}
+		if !hasChanges() {
+			return
+		}
+		
+		// Move the changes onto a fresh branch and commit there instead
+		newBranch := autoBranchPrefix + time.Now().Format("20060102-150405")
+		if dryRun {
+			fmt.Printf("  [DRY-RUN] Would move changes from protected %s to new branch %s\n", branch, newBranch)
+		} else {
+			if !createAndCheckoutBranch(newBranch) {
+				reportError(filepath.Base(repoPath), "Failed to create branch "+newBranch)
+				return
+			}
+			fmt.Printf("  🌿 %s: Moved changes from protected %s to new branch %s\n", filepath.Base(repoPath), branch, newBranch)
+			if restoreBranch {
+				defer runGit("checkout", branch)
+			}
+		}
+	}
+	protectedWarned[repoPath] = false
+	
+	// Don't pile more commits onto a branch that has diverged from its remote
+	if checkDivergence(primaryRemote(getRemotes()), branch) {
+		return
+	}
 	
 	// For monorepos: sync submodules FIRST
-	if isMonorepo(repoPath) {
+	if isMonorepo(repoPath) && !dryRun {
 		if !syncSubmodules(repoPath) {
 			fmt.Printf("  ❌ Skipping %s - submodule sync failed\n", filepath.Base(repoPath))
+			reportError(filepath.Base(repoPath), "Submodule sync failed")
 			return
 		}
 	}
-	
-	// Check if there are changes AFTER submodule sync
-	if !hasChanges() {
-		return // No changes to commit
+	
+	// Check if there are changes AFTER submodule sync
+	if !hasChanges() {
+		return // No changes to commit
+	}
+	
+	// Let a burst of saves settle into one commit; the final commit on shutdown always goes through
+	if changedWithin(config.debounceWindow) && !shuttingDown {
+		fmt.Printf("  ⏳ %s: Files changed within the %s debounce window, committing once they settle\n", filepath.Base(repoPath), config.debounceWindow)
+		return
+	}
+	
+	// Leave changes for a later pass while the last commit is too recent; the final commit on
+	// shutdown always goes through
+	if since := time.Since(lastAutoCommit[repoPath]); since < minCommitGap && !shuttingDown {
+		fmt.Printf("  ⏳ %s: Last auto-commit %s ago, waiting for -min-commit-gap %s\n",
+			filepath.Base(repoPath), since.Round(time.Second), minCommitGap)
+		return
+	}
+	
+	// Large files must be LFS-tracked before git add sees them
+	if !prepareLFS() {
+		reportError(filepath.Base(repoPath), "Large files need Git LFS, commit skipped")
+		return
+	}
+	
+	// Stage first so changes outside -include-paths don't trigger a commit
+	if !stageChanges() {
+		return
+	}
+	
+	repoName := filepath.Base(repoPath)
+	repoType := ""
+	if isMonorepo(repoPath) {
+		repoType = " [MONOREPO]"
+	}
+	fmt.Printf("📝 %s%s: Auto committing changes...\n", repoName, repoType)
+	
+	// Auto commit with monorepo-aware message
+	timestamp := time.Now().Format("2006-01-02 15:04:05")
+	commitMsg := "auto commit - " + timestamp
+	if isMonorepo(repoPath) {
+		commitMsg = "auto commit (monorepo) - " + timestamp
+	}
+	if conventional {
+		commitMsg = conventionalCommitMessage(timestamp)
+	}
+	if commitTemplate != "" {
+		commitMsg = templateCommitMessage(repoName, timestamp, commitMsg)
+	}
+	if diffStatInMessage {
+		if stat, err := getDiffStat(); err == nil {
+			commitMsg += " (" + stat.String() + ")"
+		}
+	}
+	if quietDeferred[repoPath] {
+		commitMsg = "[after quiet hours] " + commitMsg
+	}
+	if shuttingDown {
+		commitMsg = "[shutdown] " + commitMsg
+	}
+	if dryRun {
+		showDryRunCommit(commitMsg)
+		return
+	}
+	
+	if preCommitHook != "" && !runPreCommitHook() {
+		reportError(repoName, "Pre-commit hook failed, commit skipped")
+		return
+	}
+	
+	filesChanged := countChangedFiles()
+	releasing := versionFileChanged()
+	if logStagedFiles {
+		files, _ := getStagingAreaContents()
+		fmt.Printf("  📋 Staged %d files:\n", len(files))
+		for _, file := range files {
+			fmt.Printf("     %s\n", file)
+		}
+	}
+	var committed bool
+	if canAmendLastCommit() {
+		fmt.Printf("  ✏️  Amending the previous auto-commit\n")
+		committed = runGit(amendArgs()...)
+	} else if len(fileTypePrefixes) > 0 {
+		committed = commitByFileType(commitMsg)
+	} else {
+		committed = runGit(commitArgs(commitMsg)...)
+	}
+	if committed {
+		quietDeferred[repoPath] = false
+		lastAutoCommit[repoPath] = time.Now()
+		state.update(getCurrentDir(), func(repo *repoStatus) { repo.LastCommitAt = time.Now() })
+		stats.record(getCurrentDir(), func(repo *repoStats) {
+			repo.CommitCount++
+			repo.FilesCommitted += int64(filesChanged)
+		})
+		notifyEvent("commit", repoName, filesChanged)
+		handlers.onCommit(getCurrentDir(), getHeadSHA(), commitMsg)
+		recordAudit("commit", nil, filesChanged)
+		notifySlack("commit", repoName, commitMsg)
+	}
+	
+	// -batch-mode pushes and tags once every repo of the batch has committed
+	if holdPushes {
+		return
+	}
+	pushRepo(repoPath, filesChanged, commitMsg)
+	if committed {
+		tagCommit(repoPath, releasing)
+	}
+}
+
+// tagCommit creates and pushes the checkpoint and release tags due after an auto-commit, the
+// release tag when it changed -version-file
+func tagCommit(repoPath string, releasing bool) {
+	// Periodic checkpoint tags give continuous backups something to roll back to
+	if tagEvery > 0 {
+		autoCommitCounts[repoPath]++
+		if autoCommitCounts[repoPath]%tagEvery == 0 {
+			createCheckpointTag()
+		}
+	}
+	if releasing {
+		tagReleaseFromVersionFile()
+	}
+}
+
+// pushRepo squashes and pushes the current repo, then records and announces the push
+func pushRepo(repoPath string, filesChanged int, commitMsg string) {
+	repoName := filepath.Base(repoPath)
+	
+	// Fold a pile of unpushed auto-commits into one so the remote history stays readable
+	if remotes := getRemotes(); len(remotes) > 0 {
+		if shuttingDown && squashOnShutdown {
+			squashAutoCommits(remotes, getCurrentBranch(), 1)
+		} else if autoSquash {
+			squashAutoCommits(remotes, getCurrentBranch(), squashAfter)
+		}
+	}
+	
+	// Push to all remotes immediately
+	if pushToAllRemotes() {
+		var pushedTo []string
+		state.update(getCurrentDir(), func(repo *repoStatus) {
+			repo.LastPushAt = time.Now()
+			pushedTo = repo.LastPushedTo
+		})
+		recordAudit("push", pushedTo, filesChanged)
+		if githubAutoPR {
+			openPullRequest(repoPath, primaryRemote(getRemotes()), getCurrentBranch())
+		}
+		notifyEvent("push", repoName, filesChanged)
+		notifySlack("push", repoName, commitMsg)
+	}
+}
+
+// stageChanges stages everything, or only -include-paths matches when set.
+// Returns false when nothing was staged.
+func stageChanges() bool {
+	ignore, err := loadGitAirIgnore(".")
+	if err != nil {
+		fmt.Printf("  ⚠️  Reading %s: %v\n", gitAirIgnoreFile, err)
+	}
+	
+	if len(includePaths) > 0 {
+		return addPaths(includePaths, ignore)
+	}
+	if maxFileSize > 0 || excludeSymlinks || internalLinksOnly || len(ignore) > 0 {
+		return addFilteredFiles(ignore)
+	}
+	return runGit("add", ".")
+}
+
+// addFilteredFiles stages changed files one by one, leaving out .git-air-ignore matches, files
+// larger than -max-file-size-bytes and the symlinks -exclude-symlinks or -exclude-external-symlinks rule out
+func addFilteredFiles(ignore []string) bool {
+	var files, skipped, skippedLinks []string
+	for _, file := range changedFiles() {
+		if isGitAirIgnored(file, ignore) {
+			continue
+		}
+		if maxFileSize > 0 {
+			if size := fileSize(file); size > maxFileSize {
+				fmt.Printf("  ⚠️  Not staging %s (%s, limit %s)\n", file, formatBytes(size), formatBytes(maxFileSize))
+				skipped = append(skipped, file)
+				continue
+			}
+		}
+		if isExcludedSymlink(file) {
+			skippedLinks = append(skippedLinks, file)
+			continue
+		}
+		files = append(files, file)
+	}
+	if len(skipped) > 0 {
+		fmt.Printf("  ⏭️  Skipped %d oversized files: %s\n", len(skipped), strings.Join(skipped, ", "))
+	}
+	if len(skippedLinks) > 0 {
+		fmt.Printf("  ⏭️  Skipped %d symlinks: %s\n", len(skippedLinks), strings.Join(skippedLinks, ", "))
+	}
+	if len(files) == 0 {
+		return false
+	}
+	
+	runGit(append([]string{"add", "--"}, files...)...)
+	return hasStagedChanges()
+}
+
+// changedFiles lists modified, deleted and untracked (but not ignored) files
+func changedFiles() []string {
+	cmd := exec.Command("git", "ls-files", "-z", "--modified", "--deleted", "--others", "--exclude-standard")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	
+	var files []string
+	seen := map[string]bool{}
+	for _, file := range strings.Split(string(output), "\x00") {
+		if file != "" && !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// isExcludedSymlink reports whether path is a symlink that must not be committed: any symlink with
+// -exclude-symlinks, or one pointing outside the repo with -exclude-external-symlinks.
+// Absolute targets always count as outside, since they break on other machines.
+func isExcludedSymlink(path string) bool {
+	info, err := os.Lstat(path)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return false
+	}
+	if excludeSymlinks {
+		return true
+	}
+	if !internalLinksOnly {
+		return false
+	}
+	
+	target, err := os.Readlink(path)
+	if err != nil {
+		return true
+	}
+	if filepath.IsAbs(target) {
+		return true
+	}
+	// path is relative to the repo root, the current directory
+	rel := filepath.Clean(filepath.Join(filepath.Dir(path), target))
+	return rel == ".." || strings.HasPrefix(rel, "../")
+}
+
+// fileSize returns the size of a regular file, or 0 for deleted files and anything else
+func fileSize(path string) int64 {
+	info, err := os.Lstat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return 0
+	}
+	return info.Size()
+}
+
+// addPaths stages files matching the glob patterns relative to the repo root, except .git-air-ignore matches
+func addPaths(patterns, ignore []string) bool {
+	var files []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			fmt.Printf("  ⚠️  Invalid include pattern %q: %v\n", pattern, err)
+			continue
+		}
+		for _, match := range matches {
+			if !isExcludedSymlink(match) && !isGitAirIgnored(filepath.ToSlash(match), ignore) {
+				files = append(files, match)
+			}
+		}
+	}
+	if len(files) == 0 {
+		return false
+	}
+	
+	runGit(append([]string{"add", "--"}, files...)...)
+	return hasStagedChanges()
+}
+
+// isStageable reports whether stageChanges would stage the changed file, following the same
+// -include-paths, .git-air-ignore and -max-file-size-bytes rules
+func isStageable(file string, ignore []string) bool {
+	if len(includePaths) == 0 {
+		return !isGitAirIgnored(filepath.ToSlash(file), ignore) && (maxFileSize <= 0 || fileSize(file) <= maxFileSize)
+	}
+	for _, pattern := range includePaths {
+		matches, _ := filepath.Glob(pattern)
+		for _, match := range matches {
+			if (file == filepath.Clean(match) || isInside(file, match)) && !isGitAirIgnored(filepath.ToSlash(match), ignore) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasStagedChanges checks if the index differs from HEAD
+func hasStagedChanges() bool {
+	return !runGit("diff", "--cached", "--quiet")
+}
+
+// createAndCheckoutBranch creates a branch at HEAD and switches to it, keeping working tree changes
+func createAndCheckoutBranch(name string) bool {
+	return runGit("checkout", "-b", name)
+}
+
+// showDryRunCommit prints the staged changes and pushes a commit would make
+func showDryRunCommit(commitMsg string) {
+	fmt.Printf("  [DRY-RUN] Would commit: %s\n", commitMsg)
+	files, _ := getStagingAreaContents()
+	for _, file := range files {
+		fmt.Printf("  [DRY-RUN]   %s\n", file)
+	}
+	
+	branch := getCurrentBranch()
+	for _, remote := range getRemotes() {
+		fmt.Printf("  [DRY-RUN] Would push %s to %s\n", branch, remote)
+	}
+}
+
+// autoCommitTrailer ends every commit message git-air writes, whichever message format made it
+const autoCommitTrailer = "Git-Air: auto"
+
+// commitArgs builds the git arguments for an auto-commit, adding the author override and GPG signing when enabled.
+// The message gets autoCommitTrailer as its own paragraph so the commit log, undo, amend and squash can tell the commit apart.
+func commitArgs(message string) []string {
+	return gitCommitArgs("-m", message, "-m", autoCommitTrailer)
+}
+
+// amendArgs folds the staged changes into the previous commit, keeping its message
+func amendArgs() []string {
+	return gitCommitArgs("--amend", "--no-edit")
+}
+
+// gitCommitArgs builds a git commit command line with the auto-commit identity and signing options
+func gitCommitArgs(options ...string) []string {
+	args := authorArgs()
+	if gpgSign && gpgSigningKey != "" {
+		args = append(args, "-c", "user.signingkey="+gpgSigningKey)
+	}
+	
+	args = append(args, "commit")
+	if gpgSign && gpgSigningKey != "" {
+		args = append(args, "--gpg-sign="+gpgSigningKey)
+	} else if gpgSign {
+		args = append(args, "-S")
+	}
+	return append(args, options...)
+}
+
+// canAmendLastCommit reports whether -amend-window allows folding new changes into HEAD:
+// it must be a recent auto-commit that no remote has seen yet
+func canAmendLastCommit() bool {
+	if amendWindow <= 0 || time.Since(getLastCommitTime()) >= amendWindow {
+		return false
+	}
+	
+	if !isAutoCommit("HEAD") {
+		return false
+	}
+	pushed, err := isHeadPushed()
+	return err == nil && !pushed
+}
+
+// authorArgs sets the -commit-author-name/-email identity, unless -override-author-when-empty
+// is on and the repo already has a user configured
+func authorArgs() []string {
+	if overrideWhenEmpty && gitConfigValue("user.name") != "" && gitConfigValue("user.email") != "" {
+		return nil
+	}
+	
+	var args []string
+	if commitAuthorName != "" {
+		args = append(args, "-c", "user.name="+commitAuthorName)
+	}
+	if commitAuthorEmail != "" {
+		args = append(args, "-c", "user.email="+commitAuthorEmail)
+	}
+	return args
+}
+
+// gitConfigValue reads a git config value for the current repo, or "" if unset
+func gitConfigValue(key string) string {
+	cmd := exec.Command("git", "config", "--get", key)
+	output, _ := cmd.Output()
+	return strings.TrimSpace(string(output))
+}
+
+// isGPGAvailable checks for the gpg binary git uses to sign commits
+func isGPGAvailable() bool {
+	_, err := exec.LookPath("gpg")
+	return err == nil
+}
+
+// runPreCommitHook runs -pre-commit-hook with REPO_PATH and STAGED_FILES set.
+// Returns false if the hook fails or times out, in which case the commit is skipped.
+func runPreCommitHook() bool {
+	cmd := exec.Command("git", "diff", "--cached", "--name-only")
+	staged, _ := cmd.Output()
+	
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+	
+	hook := exec.CommandContext(ctx, preCommitHook)
+	hook.Env = append(os.Environ(),
+		"REPO_PATH="+getCurrentDir(),
+		"STAGED_FILES="+strings.TrimSpace(string(staged)),
+	)
+	hook.WaitDelay = time.Second // Don't wait on children still holding the output pipes
+	output, err := hook.CombinedOutput()
+	if err == nil {
+		return true
+	}
+	
+	if ctx.Err() == context.DeadlineExceeded {
+		fmt.Printf("  ❌ Pre-commit hook timed out after %s\n", hookTimeout)
+	} else {
+		fmt.Printf("  ❌ Pre-commit hook failed: %v\n", err)
+	}
+	if trimmed := strings.TrimSpace(string(output)); trimmed != "" {
+		for _, line := range strings.Split(trimmed, "\n") {
+			fmt.Printf("     %s\n", line)
+		}
+	}
+	return false
+}
+
+// templateCommitMessage renders -commit-template for the current repo, keeping fallback on failure
+func templateCommitMessage(repoName, timestamp, fallback string) string {
+	data := commitTemplateData{
+		Timestamp:    timestamp,
+		Branch:       getCurrentBranch(),
+		FilesChanged: countChangedFiles(),
+		RepoName:     repoName,
+	}
+	data.DiffStat, _ = getDiffStat()
+	data.StagedFiles, _ = getStagingAreaContents()
+	if remotes := getRemotes(); len(remotes) > 0 {
+		data.Remote = remotes[0]
+	}
+	
+	msg, err := renderCommitMessage(commitTemplate, data)
+	if err != nil || msg == "" {
+		fmt.Printf("  ⚠️  Commit template failed, using default message\n")
+		return fallback
+	}
+	return msg
+}
+
+// createCheckpointTag tags HEAD as <prefix>-<YYYYMMDD-HHMMSS> and pushes the tag to all remotes
+func createCheckpointTag() {
+	name := tagPrefix + "-" + time.Now().Format("20060102-150405")
+	if !runGit("tag", name) {
+		fmt.Printf("  ⚠️  Failed to create tag %s\n", name)
+		return
+	}
+	
+	fmt.Printf("  🏷️  Tagged checkpoint %s\n", name)
+	if config, _ := loadRepoConfig(); !config.autoPush {
+		return // Pushing the tag would publish the commit
+	}
+	for _, remote := range getRemotes() {
+		runGitRemote(remote, "push", remote, name)
+	}
+}
+
+// pullUpdates pulls from remotes for inter-project communication
+func pullUpdates(repoPath string) {
+	syncMu.Lock()
+	defer syncMu.Unlock()
+	
+	// Change to repo directory
+	oldDir, _ := os.Getwd()
+	os.Chdir(repoPath)
+	defer os.Chdir(oldDir)
+	
+	pullFromRemotes()
+}
+
+// hasChanges checks if repo has uncommitted changes
+func hasChanges() bool {
+	cmd := exec.Command("git", "status", "--porcelain")
+	output, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+	return len(strings.TrimSpace(string(output))) > 0
+}
+
+// countChangedFiles returns how many files git status reports as changed
+func countChangedFiles() int {
+	cmd := exec.Command("git", "status", "--porcelain")
+	output, err := cmd.Output()
+	if err != nil {
+		return 0
+	}
+	
+	trimmed := strings.TrimSpace(string(output))
+	if trimmed == "" {
+		return 0
+	}
+	return len(strings.Split(trimmed, "\n"))
+}
+
+// pushToAllRemotes pushes to all configured remotes, returning true if any push succeeded
+func pushToAllRemotes() bool {
+	if config, _ := loadRepoConfig(); !config.autoPush {
+		return false
+	}
+	remotes, mirrors := splitMirrorRemotes(getRemotes())
+	if len(remotes) == 0 {
+		return false
+	}
+	// A detached HEAD's commits are on no branch, and getCurrentBranch would name another one
+	if detached, _ := isDetachedHead(); detached {
+		return false
+	}
+	
+	branch := getCurrentBranch()
+	if matchesBranchPattern(branch, protectedBranches) {
+		fmt.Printf("  ⚠️  Not pushing protected branch %s\n", branch)
+		return false
+	}
+	
+	// Compare with the primary remote before pushing anything
+	primary := primaryRemote(remotes)
+	if ahead, behind, err := getAheadBehind(primary, branch); err == nil {
+		if maxAheadPush > 0 && ahead > maxAheadPush {
+			fmt.Printf("  ⚠️  %d commits ahead of %s (limit %d), not pushing\n", ahead, primary, maxAheadPush)
+			return false
+		}
+		if pullBeforePush {
+			runGitRemote(primary, "fetch", primary)
+			if _, behind, err = getAheadBehind(primary, branch); err == nil && behind > 0 {
+				fmt.Printf("  📥 %d commits behind %s, pulling before push\n", behind, primary)
+				if !pullWithStrategy(primary, branch, pullStrategy) {
+					fmt.Printf("  ❌ Pull from %s failed, not pushing\n", primary)
+					reportError(filepath.Base(getCurrentDir()), "Pull before push failed")
+					return false
+				}
+			}
+		}
+	}
+	
+	// New branches track the primary remote so plain git push works afterwards
+	upstreamRemote := ""
+	if !hasUpstream() {
+		upstreamRemote = primary
+	}
+	
+	// Push in parallel so one slow remote doesn't hold up the others
+	sem := make(chan struct{}, pushConcurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var failed, pushedTo []string
+	for _, remote := range remotes {
+		wg.Add(1)
+		go func(remote string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			
+			// Being offline isn't an error worth reporting, just try again next cycle
+			if err := checkRemote(remote); err != nil {
+				fmt.Printf("  📴 %v, skipping\n", err)
+				return
+			}
+			
+			fmt.Printf("  🚀 Push to %s\n", remote)
+			// --progress makes git report the bytes written even without a terminal
+			args := []string{"push", "--progress", remote, branch}
+			if remote == upstreamRemote {
+				args = []string{"push", "--progress", "-u", remote, branch}
+			}
+			ok := pushWithRetry(remote, args, pushRetries, pushRetryDelay)
+			
+			mu.Lock()
+			defer mu.Unlock()
+			if ok {
+				pushedTo = append(pushedTo, remote)
+			} else {
+				failed = append(failed, remote)
+			}
+		}(remote)
+	}
+	wg.Wait()
+	
+	if len(failed) == len(remotes) {
+		fmt.Printf("  ❌ Push failed to all remotes\n")
+		reportError(filepath.Base(getCurrentDir()), "Push failed to all remotes")
+		return false
+	}
+	if len(failed) > 0 {
+		fmt.Printf("  ⚠️  Push failed to %s\n", strings.Join(failed, ", "))
+		reportError(filepath.Base(getCurrentDir()), "Push failed to "+strings.Join(failed, ", "))
+	}
+	if len(pushedTo) == 0 {
+		return false
+	}
+	
+	sort.Strings(pushedTo)
+	state.update(getCurrentDir(), func(repo *repoStatus) { repo.LastPushedTo = pushedTo })
+	for _, remote := range pushedTo {
+		handlers.onPush(getCurrentDir(), remote, branch)
+	}
+	if len(mirrorBranches) == 0 || matchesBranchPattern(branch, mirrorBranches) {
+		for _, mirror := range mirrors {
+			pushMirror(mirror)
+		}
+	}
+	return true
+}
+
+// splitMirrorRemotes separates the -mirror-remotes from the remotes pushed normally
+func splitMirrorRemotes(all []string) ([]string, []string) {
+	isMirror := map[string]bool{}
+	for _, remote := range mirrorRemotes {
+		isMirror[remote] = true
+	}
+	
+	var remotes, mirrors []string
+	for _, remote := range all {
+		if isMirror[remote] {
+			mirrors = append(mirrors, remote)
+		} else {
+			remotes = append(remotes, remote)
+		}
+	}
+	return remotes, mirrors
+}
+
+// pushMirror runs git push --mirror so remote gets every ref. Failures are only warned about.
+func pushMirror(remote string) {
+	if err := verifyRemoteConnectivity(remote); err != nil {
+		fmt.Printf("  📴 Mirror %v, skipping\n", err)
+		return
+	}
+	
+	fmt.Printf("  🪞 Mirror to %s\n", remote)
+	output, err := runRemote(remote, "push", "--mirror", remote)
+	if err != nil {
+		fmt.Printf("  ⚠️  Mirror push to %s failed: %s\n", remote, gitErrorLine(output, err))
+	}
+	stats.record(getCurrentDir(), func(repo *repoStats) {
+		if err != nil {
+			repo.MirrorPushFailures++
+		} else {
+			repo.MirrorPushCount++
+		}
+	})
+}
+
+// pushWithRetry runs git push, retrying transient failures with exponential backoff
+func pushWithRetry(remote string, args []string, attempts int, baseDelay time.Duration) bool {
+	for attempt := 0; attempt < attempts; attempt++ {
+		output, err := runRemote(remote, args...)
+		if err == nil {
+			stats.record(getCurrentDir(), func(repo *repoStats) {
+				repo.PushCount++
+				repo.PushBytesTotal += parsePushBytes(string(output))
+			})
+			return true
+		}
+		
+		reason := gitErrorLine(output, err)
+		if attempt == attempts-1 {
+			fmt.Printf("  ❌ Push to %s failed after %d attempts: %s\n", remote, attempts, reason)
+			stats.countError(getCurrentDir(), "push", remote)
+			break
+		}
+		
+		delay := baseDelay * time.Duration(1<<attempt)
+		fmt.Printf("  ⚠️  Push to %s failed (attempt %d/%d): %s - retrying in %s\n", remote, attempt+1, attempts, reason, delay)
+		time.Sleep(delay)
+	}
+	return false
+}
+
+// gitErrorLine picks the most useful line from failed git output: the first
+// "fatal:" or "error:" line, else the last line, else the exec error itself.
+// Timeouts are reported as such, since git's partial output wouldn't say so.
+func gitErrorLine(output []byte, err error) string {
+	if errors.Is(err, errNetworkTimeout) {
+		return err.Error()
+	}
+	
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	for _, line := range lines {
+		if strings.HasPrefix(line, "fatal:") || strings.HasPrefix(line, "error:") {
+			return strings.TrimSpace(line)
+		}
+	}
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+		return last
+	}
+	return err.Error()
+}
+
+// primaryRemote picks origin if present, otherwise the first remote
+func primaryRemote(remotes []string) string {
+	for _, remote := range remotes {
+		if remote == "origin" {
+			return remote
+		}
+	}
+	if len(remotes) == 0 {
+		return ""
+	}
+	return remotes[0]
+}
+
+// getAheadBehind counts commits HEAD has that remote/branch lacks (ahead) and the reverse (behind)
+func getAheadBehind(remote, branch string) (int, int, error) {
+	cmd := exec.Command("git", "rev-list", "--left-right", "--count", "HEAD..."+remote+"/"+branch)
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, 0, err
+	}
+	return parseAheadBehind(string(output))
+}
+
+// getLastCommitTime returns when HEAD was committed, or the zero time for an empty repo
+func getLastCommitTime() time.Time {
+	cmd := exec.Command("git", "log", "-1", "--format=%ct")
+	output, err := cmd.Output()
+	if err != nil {
+		return time.Time{}
+	}
+	seconds, err := strconv.ParseInt(strings.TrimSpace(string(output)), 10, 64)
+	if err != nil {
+		return time.Time{}
 	}
-	
-	repoName := filepath.Base(repoPath)
-	repoType := ""
-	if isMonorepo(repoPath) {
-		repoType = " [MONOREPO]"
+	return time.Unix(seconds, 0)
+}
+
+// checkInactive reports whether the current repo has gone longer than -inactive-threshold
+// without a commit, announcing when a repo goes idle or becomes active again
+func checkInactive() bool {
+	if inactiveAfter <= 0 {
+		return false
 	}
-	fmt.Printf("📝 %s%s: Auto committing changes...\n", repoName, repoType)
 	
-	// Auto commit with monorepo-aware message
-	runGit("add", ".")
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	commitMsg := "auto commit - " + timestamp
-	if isMonorepo(repoPath) {
-		commitMsg = "auto commit (monorepo) - " + timestamp
+	lastCommit := getLastCommitTime()
+	if humanCommitsOnly {
+		lastCommit = getLastHumanCommitTime()
 	}
-	runGit("commit", "-m", commitMsg)
+	inactive := !lastCommit.IsZero() && time.Since(lastCommit) > inactiveAfter
 	
-	// Push to all remotes immediately
-	pushToAllRemotes()
+	repoPath := getCurrentDir()
+	wasInactive := false
+	state.update(repoPath, func(repo *repoStatus) {
+		wasInactive = repo.Inactive
+		repo.Inactive = inactive
+	})
+	
+	if inactive && !wasInactive {
+		who := ""
+		if humanCommitsOnly {
+			who = " by a person"
+		}
+		fmt.Printf("  💤 %s: No commits%s since %s, skipping as inactive\n", filepath.Base(repoPath), who, lastCommit.Format("2006-01-02"))
+	} else if !inactive && wasInactive {
+		fmt.Printf("  ⏰ %s: Active again\n", filepath.Base(repoPath))
+	}
+	return inactive
 }
 
-// pullUpdates pulls from remotes for inter-project communication
-func pullUpdates(repoPath string) {
-	// Change to repo directory
-	oldDir, _ := os.Getwd()
-	os.Chdir(repoPath)
-	defer os.Chdir(oldDir)
-	
-	pullFromRemotes()
+// isDiverged reports whether HEAD and remote/branch both have commits the other lacks
+func isDiverged(remote, branch string) (bool, error) {
+	ahead, behind, err := getAheadBehind(remote, branch)
+	if err != nil {
+		return false, err
+	}
+	return ahead > 0 && behind > 0, nil
 }
 
-// hasChanges checks if repo has uncommitted changes
-func hasChanges() bool {
-	cmd := exec.Command("git", "status", "--porcelain")
-	output, err := cmd.Output()
+// checkDivergence pauses auto operations on the current repo while its branch has diverged
+// from remote and resumes them once someone has reconciled it. Returns true while paused.
+func checkDivergence(remote, branch string) bool {
+	if !pauseOnDiverge || remote == "" {
+		return false
+	}
+	
+	diverged, err := isDiverged(remote, branch)
 	if err != nil {
 		return false
 	}
-	return len(strings.TrimSpace(string(output))) > 0
+	
+	repoPath := getCurrentDir()
+	repoName := filepath.Base(repoPath)
+	wasDiverged := false
+	state.update(repoPath, func(repo *repoStatus) {
+		wasDiverged = repo.Diverged
+		repo.Diverged = diverged
+		if diverged && !wasDiverged {
+			repo.DivergedAt = time.Now()
+		} else if !diverged {
+			repo.DivergedAt = time.Time{}
+		}
+	})
+	
+	if diverged && !wasDiverged {
+		cmd := exec.Command("git", "rev-parse", remote+"/"+branch)
+		remoteSHA, _ := cmd.Output()
+		message := fmt.Sprintf("Branch %s diverged from %s (local %s, remote %s), auto operations paused until resolved",
+			branch, remote, getHeadSHA(), strings.TrimSpace(string(remoteSHA)))
+		fmt.Printf("  ❌ %s: %s\n", repoName, message)
+		reportError(repoName, message)
+	} else if !diverged && wasDiverged {
+		fmt.Printf("  ▶️  %s: Branch %s no longer diverged from %s, resuming\n", repoName, branch, remote)
+	}
+	return diverged
 }
 
-// pushToAllRemotes pushes to all configured remotes
-func pushToAllRemotes() {
-	remotes := getRemotes()
-	if len(remotes) == 0 {
-		return
+// parseAheadBehind parses "<ahead>\t<behind>" as printed by git rev-list --left-right --count
+func parseAheadBehind(output string) (int, int, error) {
+	fields := strings.Fields(output)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output %q", output)
 	}
 	
-	branch := getCurrentBranch()
-	for _, remote := range remotes {
-		fmt.Printf("  🚀 Push to %s\n", remote)
-		runGit("push", remote, branch)
+	ahead, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, 0, err
 	}
+	behind, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return ahead, behind, nil
+}
+
+// hasUpstream checks if the current branch has an upstream tracking branch
+func hasUpstream() bool {
+	return runGit("rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{u}")
 }
 
 // pullFromRemotes pulls from remotes for inter-project communication
 func pullFromRemotes() {
+	if config, _ := loadRepoConfig(); !config.autoPull {
+		return
+	}
 	remotes := getRemotes()
 	if len(remotes) == 0 {
 		return
 	}
 	
+	// getCurrentBranch falls back to the default branch on a detached HEAD, which mustn't be pulled into it
+	if detached, _ := isDetachedHead(); detached {
+		return
+	}
 	branch := getCurrentBranch()
 	repoName := filepath.Base(getCurrentDir())
+	if !isBranchAllowed(branch) || checkInactive() || isTooLarge() {
+		return
+	}
 	
 	// Try to pull from each remote
 	for _, remote := range remotes {
+		if err := checkRemote(remote); err != nil {
+			fmt.Printf("  📴 %s: %v, skipping\n", repoName, err)
+			continue
+		}
+		
 		fmt.Printf("  📥 %s: Checking %s for updates\n", repoName, remote)
-		runGit("fetch", remote)
+		runGitRemote(remote, "fetch", remote)
+		
+		if pruneMerged && remote == primaryRemote(remotes) {
+			deleted, err := pruneStaleBranches(remote)
+			if err != nil {
+				fmt.Printf("  ⚠️  %s: %v\n", repoName, err)
+			} else if len(deleted) > 0 {
+				fmt.Printf("  🧹 %s: Deleted merged branches %s\n", repoName, strings.Join(deleted, ", "))
+			}
+		}
+		
+		// Leave diverged branches for a human to reconcile
+		if remote == primaryRemote(remotes) && checkDivergence(remote, branch) {
+			return
+		}
 		
 		// Check if there are remote changes
 		if hasRemoteChanges(remote, branch) {
+			// Don't pull if the merge would leave conflict markers behind
+			if prediction := detectConflicts(remote, branch); len(prediction.LikelyConflicts) > 0 {
+				conflicts := strings.Join(prediction.LikelyConflicts, ", ")
+				fmt.Printf("  ⚠️  %s: Skipping pull from %s - conflicts likely in %s (%.0f%% of changed files overlap)\n",
+					repoName, remote, conflicts, prediction.Confidence*100)
+				reportError(repoName, "Pull from "+remote+" skipped, conflicts likely in "+conflicts)
+				stats.record(getCurrentDir(), func(repo *repoStats) { repo.ConflictsAvoided++ })
+				continue
+			}
+			
+			if dryRun {
+				fmt.Printf("  [DRY-RUN] %s: Would pull %s from %s\n", repoName, branch, remote)
+				continue
+			}
+			
 			fmt.Printf("  📡 %s: Pulling inter-project updates from %s\n", repoName, remote)
-			runGit("pull", remote, branch)
+			var pulled bool
+			if stashBeforePull && hasChanges() {
+				pulled = stashAndPull(remote, branch, pullStrategy)
+			} else {
+				pulled = pullWithStrategy(remote, branch, pullStrategy)
+			}
+			
+			if pulled {
+				state.update(getCurrentDir(), func(repo *repoStatus) { repo.LastPullAt = time.Now() })
+				handlers.onPullComplete(getCurrentDir(), remote)
+				stats.record(getCurrentDir(), func(repo *repoStats) { repo.PullCount++ })
+			} else {
+				stats.countError(getCurrentDir(), "pull", remote)
+				reportError(repoName, "Pull from "+remote+" failed")
+				if resetOnConflict && remote == primaryRemote(remotes) {
+					if _, err := resetToRemote(remote, branch); err != nil {
+						fmt.Printf("  ❌ %s: Hard reset failed: %v\n", repoName, err)
+					}
+					return
+				}
+			}
 		}
 	}
 }
 
-// getRemotes returns list of remote names
+// pullWithStrategy pulls remote/branch using merge, rebase or ff-only
+func pullWithStrategy(remote, branch, strategy string) bool {
+	strategyFlags, ok := pullStrategies[strategy]
+	if !ok {
+		fmt.Printf("  ❌ Unknown pull strategy %q\n", strategy)
+		return false
+	}
+	
+	args := append([]string{"pull"}, strategyFlags...)
+	args = append(args, remote, branch)
+	return runGitRemote(remote, args...)
+}
+
+// stashAndPull stashes uncommitted changes, including untracked files, pulls, then pops the stash again.
+// Only a stash this call created is popped, so an existing stash of the user's is never applied.
+// If popping conflicts the working tree is reset to the pulled commit and the stash is kept,
+// its SHA recorded as the repo's pending stash so it can be applied by hand or via POST /stash/apply.
+func stashAndPull(remote, branch, strategy string) bool {
+	before := stashHead()
+	if !runGit("stash", "push", "--include-untracked", "-m", "git-air auto-stash") {
+		fmt.Printf("  ❌ Failed to stash local changes, skipping pull\n")
+		return false
+	}
+	ref := stashHead()
+	stashed := ref != "" && ref != before
+	
+	pulled := pullWithStrategy(remote, branch, strategy)
+	
+	if stashed && !runGit("stash", "pop") {
+		// Don't leave conflict markers for the next auto-commit; the changes are safe in the stash
+		runGit("reset", "--merge")
+		state.update(getCurrentDir(), func(repo *repoStatus) { repo.PendingStash = ref })
+		fmt.Printf("  ⚠️  Stash pop conflicted - local changes kept in the stash, apply with: git stash apply %s\n", ref)
+		reportError(filepath.Base(getCurrentDir()), "Stash pop conflicted after pull, changes kept in stash "+ref)
+		return pulled
+	}
+	
+	return pulled
+}
+
+// stashHead returns the SHA of the newest stash entry, or "" when the stash is empty
+func stashHead() string {
+	cmd := exec.Command("git", "rev-parse", "-q", "--verify", "refs/stash")
+	output, _ := cmd.Output()
+	return strings.TrimSpace(string(output))
+}
+
+// getRemotes returns list of remote names, keeping one remote per repository URL
+// so the same server isn't pushed to twice
 func getRemotes() []string {
-	cmd := exec.Command("git", "remote")
+	remotes, _ := dedupRemotes(getRawRemotes())
+	return remotes
+}
+
+// getRawRemotes maps every remote name to its URL
+func getRawRemotes() map[string]string {
+	cmd := exec.Command("git", "config", "--get-regexp", `^remote\..*\.url$`)
 	output, err := cmd.Output()
 	if err != nil {
-		return []string{}
+		return map[string]string{}
 	}
 	
-	remotes := strings.Fields(string(output))
+	remotes := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		key, url, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		remotes[strings.TrimSuffix(strings.TrimPrefix(key, "remote."), ".url")] = url
+	}
 	return remotes
 }
 
+// dedupRemotes keeps one remote per normalized URL, preferring SSH over HTTPS and then origin,
+// and returns the kept remote names (sorted) along with the dropped ones
+func dedupRemotes(remotes map[string]string) ([]string, []string) {
+	names := make([]string, 0, len(remotes))
+	for name := range remotes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	
+	best := map[string]string{}
+	for _, name := range names {
+		key := normalizeRemoteURL(remotes[name])
+		current, seen := best[key]
+		if !seen || remoteRank(name, remotes[name]) > remoteRank(current, remotes[current]) {
+			best[key] = name
+		}
+	}
+	
+	var kept, dropped []string
+	for _, name := range names {
+		if best[normalizeRemoteURL(remotes[name])] == name {
+			kept = append(kept, name)
+		} else {
+			dropped = append(dropped, name)
+		}
+	}
+	return kept, dropped
+}
+
+// remoteRank orders duplicate remotes: SSH beats HTTPS, then origin beats other names
+func remoteRank(name, url string) int {
+	rank := 0
+	if isSSHURL(url) {
+		rank += 2
+	}
+	if name == "origin" {
+		rank++
+	}
+	return rank
+}
+
+// isSSHURL reports whether url is ssh://... or scp-like user@host:path
+func isSSHURL(url string) bool {
+	if strings.HasPrefix(url, "ssh://") || strings.HasPrefix(url, "git+ssh://") {
+		return true
+	}
+	return !strings.Contains(url, "://") && strings.Contains(url, "@") && strings.Contains(url, ":")
+}
+
+// normalizeRemoteURL reduces git@host:user/repo.git, ssh://git@host:22/user/repo and
+// https://host/user/repo.git to the same "host/user/repo" form
+func normalizeRemoteURL(url string) string {
+	url = strings.TrimSpace(url)
+	if scheme, rest, ok := strings.Cut(url, "://"); ok {
+		if scheme == "file" {
+			return filepath.Clean(rest)
+		}
+		url = rest
+		if at := strings.Index(url, "@"); at >= 0 && at < strings.Index(url+"/", "/") {
+			url = url[at+1:]
+		}
+		host, path, _ := strings.Cut(url, "/")
+		host, _, _ = strings.Cut(host, ":") // Drop the port
+		url = host + "/" + path
+	} else if isSSHURL(url) {
+		url = url[strings.Index(url, "@")+1:]
+		url = strings.Replace(url, ":", "/", 1)
+	} else {
+		return filepath.Clean(url) // Local path
+	}
+	
+	url = strings.TrimSuffix(strings.TrimSuffix(url, "/"), ".git")
+	host, path, _ := strings.Cut(url, "/")
+	return strings.ToLower(host) + "/" + path
+}
+
 // getCurrentBranch returns current branch name
 func getCurrentBranch() string {
 	cmd := exec.Command("git", "branch", "--show-current")
 	output, err := cmd.Output()
-	if err != nil {
-		return "main" // fallback
+	if name := strings.TrimSpace(string(output)); err == nil && name != "" {
+		return name
 	}
-	return strings.TrimSpace(string(output))
+	
+	// A detached HEAD prints nothing, so fall back to the primary remote's default branch, then to "main"
+	if name, err := getRemoteDefaultBranch(primaryRemote(getRemotes())); err == nil {
+		return name
+	}
+	return "main"
+}
+
+// isDetachedHead reports whether HEAD is detached, along with the commit it points at
+func isDetachedHead() (bool, string) {
+	cmd := exec.Command("git", "symbolic-ref", "-q", "HEAD")
+	if err := cmd.Run(); err == nil {
+		return false, ""
+	}
+	sha := getHeadSHA()
+	return sha != "", sha
+}
+
+// isBranchAllowed checks a branch against the allowed and blocked patterns.
+// Blocked patterns win, and an empty allow list allows every branch.
+func isBranchAllowed(branch string) bool {
+	if matchesBranchPattern(branch, blockedBranches) {
+		return false
+	}
+	if len(allowedBranches) == 0 {
+		return true
+	}
+	return matchesBranchPattern(branch, allowedBranches)
+}
+
+// matchesBranchPattern reports whether branch matches any of the glob patterns.
+// Like filepath.Match, "*" does not cross "/" so "feature/*" won't match "feature/foo/bar".
+func matchesBranchPattern(branch string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, err := filepath.Match(pattern, branch); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPathPattern matches a slash-separated path against a glob pattern in which
+// "**" stands for any number of directories, e.g. "**/node_modules/**"
+func matchPathPattern(pattern, path string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(path, "/"))
+}
+
+func matchSegments(pattern, path []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(path); i++ {
+				if matchSegments(pattern[1:], path[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(path) == 0 {
+			return false
+		}
+		if matched, err := filepath.Match(pattern[0], path[0]); err != nil || !matched {
+			return false
+		}
+		pattern, path = pattern[1:], path[1:]
+	}
+	return len(path) == 0
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// reportError records a sync failure for the status API and notifies Slack
+func reportError(repoName, message string) {
+	recordError(getCurrentDir(), message)
+	handlers.onError(getCurrentDir(), errors.New(message))
+	notifySlack("error", repoName, message)
 }
 
 // runGit runs a git command and returns success
@@ -207,6 +2070,81 @@ func runGit(args ...string) bool {
 	return true
 }
 
+// runGitRemote is runGit for commands that talk to remote, using its -remote-ssh-keys key
+// and giving up after -network-timeout
+func runGitRemote(remote string, args ...string) bool {
+	_, err := runRemote(remote, args...)
+	if errors.Is(err, errNetworkTimeout) {
+		fmt.Printf("  ⏱️  %v\n", err)
+	}
+	return err == nil
+}
+
+// errNetworkTimeout marks git commands killed after -network-timeout
+var errNetworkTimeout = errors.New("network operation timed out")
+
+// runRemote runs a git command that talks to remote with its SSH key, killing it after -network-timeout
+// so a stalled connection can't hang the sync loop
+func runRemote(remote string, args ...string) ([]byte, error) {
+	return runRemoteIn("", remote, args...)
+}
+
+// runRemoteIn is runRemote in the repo at dir instead of the current directory
+func runRemoteIn(dir, remote string, args ...string) ([]byte, error) {
+	// Waiting for a slot doesn't count towards the timeout
+	acquireOpSlot(context.Background())
+	defer releaseOpSlot()
+	
+	ctx, cancel := context.WithTimeout(context.Background(), networkTimeout)
+	defer cancel()
+	
+	cmd := withSSHKey(exec.CommandContext(ctx, "git", args...), remote)
+	cmd.Dir = dir
+	cmd.WaitDelay = time.Second
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return output, fmt.Errorf("git %s %s: %w after %s", args[0], remote, errNetworkTimeout, networkTimeout)
+	}
+	return output, err
+}
+
+// withSSHKey makes cmd authenticate with the key configured for remote, if there is one
+func withSSHKey(cmd *exec.Cmd, remote string) *exec.Cmd {
+	if key := remoteSSHKeys[remote]; key != "" {
+		cmd.Env = append(os.Environ(), sshCommandEnv(key))
+	}
+	return cmd
+}
+
+// sshCommandEnv builds the GIT_SSH_COMMAND that uses only the given private key
+func sshCommandEnv(keyPath string) string {
+	quoted := "'" + strings.ReplaceAll(keyPath, "'", `'\''`) + "'"
+	return "GIT_SSH_COMMAND=ssh -i " + quoted + " -o IdentitiesOnly=yes -o StrictHostKeyChecking=accept-new"
+}
+
+// parseRemoteSSHKeys parses "remote=keyfile" pairs, expanding ~ and checking the keys exist
+func parseRemoteSSHKeys(value string) (map[string]string, error) {
+	keys := map[string]string{}
+	for _, pair := range splitList(value) {
+		remote, keyPath, ok := strings.Cut(pair, "=")
+		if !ok || remote == "" || keyPath == "" {
+			return nil, fmt.Errorf("%q must look like remote=keyfile", pair)
+		}
+		if strings.HasPrefix(keyPath, "~/") {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, err
+			}
+			keyPath = filepath.Join(home, keyPath[2:])
+		}
+		if _, err := os.Stat(keyPath); err != nil {
+			return nil, err
+		}
+		keys[remote] = keyPath
+	}
+	return keys, nil
+}
+
 // hasRemoteChanges checks if remote has changes
 func hasRemoteChanges(remote, branch string) bool {
 	cmd := exec.Command("git", "rev-parse", "HEAD")
@@ -224,14 +2162,90 @@ func hasRemoteChanges(remote, branch string) bool {
 	return string(localOut) != string(remoteOut)
 }
 
+// conflictPrediction lists the files changed on both sides since the merge base. Confidence is the
+// share of the larger side's changes that overlap, so 1 means every changed file is contested.
+type conflictPrediction struct {
+	LikelyConflicts []string
+	Confidence      float64
+}
+
+// detectConflicts predicts which files would conflict when merging remote/branch into HEAD by
+// intersecting the files each side changed since their merge base. The working tree is never touched.
+func detectConflicts(remote, branch string) conflictPrediction {
+	base, err := getMergeBase("HEAD", remote+"/"+branch)
+	if err != nil {
+		return conflictPrediction{} // No common history, let pull report it
+	}
+	local, err := changedFilesBetween(base, "HEAD")
+	if err != nil {
+		return conflictPrediction{}
+	}
+	incoming, err := changedFilesBetween(base, remote+"/"+branch)
+	if err != nil {
+		return conflictPrediction{}
+	}
+	
+	changedLocally := map[string]bool{}
+	for _, path := range local {
+		changedLocally[path] = true
+	}
+	var prediction conflictPrediction
+	for _, path := range incoming {
+		if changedLocally[path] {
+			prediction.LikelyConflicts = append(prediction.LikelyConflicts, path)
+		}
+	}
+	if len(prediction.LikelyConflicts) > 0 {
+		larger := len(local)
+		if len(incoming) > larger {
+			larger = len(incoming)
+		}
+		prediction.Confidence = float64(len(prediction.LikelyConflicts)) / float64(larger)
+	}
+	return prediction
+}
+
+// getMergeBase returns the best common ancestor of two commits
+func getMergeBase(rev1, rev2 string) (string, error) {
+	cmd := exec.Command("git", "merge-base", rev1, rev2)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git merge-base %s %s: %v", rev1, rev2, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// changedFilesBetween lists the files that differ between two commits
+func changedFilesBetween(from, to string) ([]string, error) {
+	cmd := exec.Command("git", "diff", "--name-only", "--no-renames", from, to)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff %s %s: %v", from, to, err)
+	}
+	if strings.TrimSpace(string(output)) == "" {
+		return nil, nil
+	}
+	return strings.Split(strings.TrimSpace(string(output)), "\n"), nil
+}
+
+// getHeadSHA returns the commit SHA of HEAD
+func getHeadSHA() string {
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
 // getCurrentDir returns current directory
 func getCurrentDir() string {
 	dir, _ := os.Getwd()
 	return dir
 }
 
-// isMonorepo checks if a repository contains submodules or nested repos
-func isMonorepo(repoPath string) bool {
+// detectMonorepo checks if a repository contains submodules or nested repos
+func detectMonorepo(repoPath string) bool {
 	// Check for .gitmodules file (Git submodules)
 	gitmodules := filepath.Join(repoPath, ".gitmodules")
 	if _, err := os.Stat(gitmodules); err == nil {
@@ -271,6 +2285,11 @@ func syncSubmodules(repoPath string) bool {
 	
 	fmt.Printf("  📦 Syncing submodules in monorepo...\n")
 	
+	// Commit work inside submodules first so the update below doesn't trip over it
+	if submoduleCommit {
+		commitSubmodules("auto commit (submodule) - " + time.Now().Format("2006-01-02 15:04:05"))
+	}
+	
 	// Update all submodules
 	if !runGit("submodule", "update", "--remote", "--merge") {
 		fmt.Printf("  ⚠️  Submodule update failed\n")
@@ -282,4 +2301,94 @@ func syncSubmodules(repoPath string) bool {
 	
 	fmt.Printf("  ✅ Submodules synced\n")
 	return true
+}
+
+// submoduleEntry is one [submodule "name"] section of .gitmodules
+type submoduleEntry struct {
+	Name string
+	Path string
+	URL  string
+}
+
+// parseGitmodules reads the submodule sections of a .gitmodules file
+func parseGitmodules(path string) ([]submoduleEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	
+	var entries []submoduleEntry
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[submodule ") {
+			name := strings.Trim(strings.TrimPrefix(line, "[submodule "), "\"]")
+			entries = append(entries, submoduleEntry{Name: name})
+			continue
+		}
+		
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || len(entries) == 0 {
+			continue
+		}
+		entry := &entries[len(entries)-1]
+		switch strings.TrimSpace(key) {
+		case "path":
+			entry.Path = strings.TrimSpace(value)
+		case "url":
+			entry.URL = strings.TrimSpace(value)
+		}
+	}
+	return entries, nil
+}
+
+// commitSubmodules commits and pushes changes inside every submodule of the current repo,
+// depth first, then stages the new submodule refs in the parent
+func commitSubmodules(message string) {
+	entries, err := parseGitmodules(".gitmodules")
+	if err != nil {
+		return
+	}
+	
+	parentDir, _ := os.Getwd()
+	for _, entry := range entries {
+		if entry.Path == "" {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(entry.Path, ".git")); err != nil {
+			continue // Not checked out
+		}
+		
+		os.Chdir(filepath.Join(parentDir, entry.Path))
+		commitSubmodules(message)
+		commitSubmodule(entry.Path, message)
+		os.Chdir(parentDir)
+		
+		runGit("add", entry.Path)
+	}
+}
+
+// commitSubmodule runs the add and commit cycle inside the current submodule and pushes the result
+func commitSubmodule(path, message string) {
+	if !hasChanges() {
+		return
+	}
+	
+	// Submodules usually sit on a detached HEAD, which has no branch to commit onto and push
+	if detached, sha := isDetachedHead(); detached {
+		fmt.Printf("  ⚠️  Submodule %s has changes on a detached HEAD at %.7s, leaving them uncommitted\n", path, sha)
+		return
+	}
+	branch := getCurrentBranch()
+	if !isBranchAllowed(branch) || matchesBranchPattern(branch, protectedBranches) {
+		fmt.Printf("  ⚠️  Submodule %s has changes on %q, leaving them uncommitted\n", path, branch)
+		return
+	}
+	
+	runGit("add", ".")
+	if !runGit(commitArgs(message)...) {
+		fmt.Printf("  ⚠️  Commit in submodule %s failed\n", path)
+		return
+	}
+	fmt.Printf("  📦 Committed submodule %s\n", path)
+	pushToAllRemotes()
 }
\ No newline at end of file
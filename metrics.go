@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// startMetricsServer serves Prometheus metrics at GET /metrics on addr in the background
+func startMetricsServer(addr string) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatalf("Metrics server: %v", err)
+	}
+	
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", metricsHandler)
+	
+	fmt.Printf("📈 Metrics listening on %s\n", listener.Addr())
+	go func() {
+		if err := http.Serve(listener, mux); err != nil {
+			log.Printf("Metrics server stopped: %v", err)
+		}
+	}()
+}
+
+// metricsHandler renders the sync state and statistics in the Prometheus text format
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	writeMetrics(w, state.snapshot(), stats.snapshot())
+}
+
+// writeMetrics writes one HELP/TYPE block per metric followed by its samples
+func writeMetrics(w io.Writer, repos []repoStatus, counters map[string]repoStats) {
+	paths := make([]string, 0, len(counters))
+	for path := range counters {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	
+	writeMetricHeader(w, "gitair_commits_total", "counter", "Auto-commits made.")
+	for _, path := range paths {
+		fmt.Fprintf(w, "gitair_commits_total{repo=%s} %d\n", labelValue(path), counters[path].CommitCount)
+	}
+	
+	writeMetricHeader(w, "gitair_file_events_total", "counter", "Changed files picked up by auto-commits.")
+	for _, path := range paths {
+		fmt.Fprintf(w, "gitair_file_events_total{repo=%s} %d\n", labelValue(path), counters[path].FilesCommitted)
+	}
+	
+	writeMetricHeader(w, "gitair_push_errors_total", "counter", "Pushes that failed after all retries.")
+	for _, path := range paths {
+		writeRemoteCounts(w, "gitair_push_errors_total", path, counters[path].PushErrors)
+	}
+	
+	writeMetricHeader(w, "gitair_pull_errors_total", "counter", "Pulls that failed.")
+	for _, path := range paths {
+		writeRemoteCounts(w, "gitair_pull_errors_total", path, counters[path].PullErrors)
+	}
+	
+	active := 0
+	for _, repo := range repos {
+		if !repo.Inactive {
+			active++
+		}
+	}
+	writeMetricHeader(w, "gitair_repos_active", "gauge", "Repositories being synced.")
+	fmt.Fprintf(w, "gitair_repos_active %d\n", active)
+	
+	writeMetricHeader(w, "gitair_concurrent_ops", "gauge", "Network git operations running now (limit -max-concurrent-ops).")
+	fmt.Fprintf(w, "gitair_concurrent_ops %d\n", opsInFlight())
+	
+	writeMetricHeader(w, "gitair_last_commit_timestamp", "gauge", "Unix time of the last auto-commit.")
+	for _, repo := range repos {
+		if !repo.LastCommitAt.IsZero() {
+			fmt.Fprintf(w, "gitair_last_commit_timestamp{repo=%s} %d\n", labelValue(repo.Repo), repo.LastCommitAt.Unix())
+		}
+	}
+}
+
+func writeMetricHeader(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func writeRemoteCounts(w io.Writer, name, path string, counts map[string]int64) {
+	remotes := make([]string, 0, len(counts))
+	for remote := range counts {
+		remotes = append(remotes, remote)
+	}
+	sort.Strings(remotes)
+	for _, remote := range remotes {
+		fmt.Fprintf(w, "%s{repo=%s,remote=%s} %d\n", name, labelValue(path), labelValue(remote), counts[remote])
+	}
+}
+
+// labelValue quotes a label value, escaping backslashes, quotes and newlines
+func labelValue(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
\ No newline at end of file
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// webhookPayload is the JSON body posted to -webhook-url
+type webhookPayload struct {
+	Event        string    `json:"event"`
+	RepoName     string    `json:"repoName"`
+	Branch       string    `json:"branch"`
+	CommitSHA    string    `json:"commitSha"`
+	Timestamp    time.Time `json:"timestamp"`
+	FilesChanged int       `json:"filesChanged"`
+}
+
+// notifyEvent posts an event for the current repo to -webhook-url in the background
+func notifyEvent(event, repoName string, filesChanged int) {
+	if webhookURL == "" {
+		return
+	}
+	
+	payload := webhookPayload{
+		Event:        event,
+		RepoName:     repoName,
+		Branch:       getCurrentBranch(),
+		CommitSHA:    getHeadSHA(),
+		Timestamp:    time.Now(),
+		FilesChanged: filesChanged,
+	}
+	go func() {
+		if err := notifyWebhook(webhookURL, webhookSecret, payload); err != nil {
+			fmt.Printf("  ⚠️  Webhook %s for %s failed: %v\n", event, repoName, err)
+		}
+	}()
+}
+
+// notifyWebhook POSTs the payload as JSON, signed with HMAC-SHA256 in X-Git-Air-Signature.
+// Failed deliveries are retried up to 3 times with exponential backoff.
+func notifyWebhook(url, secret string, payload webhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	
+	delay := time.Second
+	for attempt := 0; ; attempt++ {
+		err = postWebhook(url, signature, body)
+		if err == nil || attempt == 3 {
+			return err
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// postWebhook makes a single delivery attempt
+func postWebhook(url, signature string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Git-Air-Signature", signature)
+	
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// slackNotifier posts Block Kit messages to a Slack incoming webhook
+type slackNotifier struct {
+	webhookURL string
+	channel    string
+	onCommit   bool
+	onError    bool
+}
+
+// slack is set when -slack-webhook-url is configured
+var slack *slackNotifier
+
+// notifySlack sends a commit, push or error event for the current repo to Slack in the background
+func notifySlack(event, repoName, message string) {
+	if slack == nil {
+		return
+	}
+	if event == "error" && !slack.onError || event != "error" && !slack.onCommit {
+		return
+	}
+	
+	branch := getCurrentBranch()
+	go func() {
+		if err := slack.send(event, repoName, branch, message); err != nil {
+			fmt.Printf("  ⚠️  Slack %s for %s failed: %v\n", event, repoName, err)
+		}
+	}()
+}
+
+// send posts one event with repo, branch and a truncated message
+func (n *slackNotifier) send(event, repo, branch, message string) error {
+	if runes := []rune(message); len(runes) > 150 {
+		message = string(runes[:150]) + "…"
+	}
+	
+	payload := map[string]interface{}{
+		"text": fmt.Sprintf("git-air %s in %s: %s", event, repo, message),
+		"blocks": []interface{}{
+			map[string]interface{}{
+				"type": "header",
+				"text": map[string]string{"type": "plain_text", "text": "git-air: " + event},
+			},
+			map[string]interface{}{
+				"type": "section",
+				"fields": []map[string]string{
+					{"type": "mrkdwn", "text": "*Repository:*\n" + repo},
+					{"type": "mrkdwn", "text": "*Branch:*\n" + branch},
+				},
+			},
+			map[string]interface{}{
+				"type": "section",
+				"text": map[string]string{"type": "mrkdwn", "text": "```" + message + "```"},
+			},
+		},
+	}
+	if n.channel != "" {
+		payload["channel"] = n.channel
+	}
+	
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(n.webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("slack returned %s", resp.Status)
+	}
+	return nil
+}
\ No newline at end of file
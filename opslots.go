@@ -0,0 +1,32 @@
+package main
+
+import "context"
+
+// opSlots caps how many network git commands (push, fetch, pull, ls-remote) run at once across
+// all repos, so parallel pushes can't exhaust file descriptors or SSH connections. Local git
+// commands are already serialised by syncMu. Nil means unlimited.
+var opSlots chan struct{}
+
+// acquireOpSlot waits for a free slot or until ctx is done
+func acquireOpSlot(ctx context.Context) error {
+	if opSlots == nil {
+		return nil
+	}
+	select {
+	case opSlots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func releaseOpSlot() {
+	if opSlots != nil {
+		<-opSlots
+	}
+}
+
+// opsInFlight is the number of slots in use, exported as gitair_concurrent_ops
+func opsInFlight() int {
+	return len(opSlots)
+}
\ No newline at end of file
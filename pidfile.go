@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// acquirePIDFiles claims each repo by writing our PID to -pid-file inside it.
+// Repos claimed by another running git-air are skipped, or fatal with -fail-on-existing-pid.
+func acquirePIDFiles(repos []string) []string {
+	if pidFile == "" {
+		return repos
+	}
+	
+	var claimed []string
+	for _, repo := range repos {
+		path := filepath.Join(repo, pidFile)
+		
+		if data, err := os.ReadFile(path); err == nil {
+			pid, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+			if pid > 0 && pid != os.Getpid() && isProcessRunning(pid) {
+				if failOnExistingPID {
+					log.Fatalf("%s is already managed by git-air (PID %d)", repo, pid)
+				}
+				fmt.Printf("  ⚠️  %s: Already managed by git-air (PID %d), skipping\n", repo, pid)
+				continue
+			}
+			if pid != os.Getpid() {
+				fmt.Printf("  ⚠️  %s: Removing stale PID file for PID %d\n", repo, pid)
+			}
+		}
+		
+		if err := os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())+"\n"), 0644); err != nil {
+			fmt.Printf("  ⚠️  %s: Could not write PID file: %v\n", repo, err)
+		}
+		claimed = append(claimed, repo)
+	}
+	return claimed
+}
+
+// releasePIDFiles removes the PID files written by acquirePIDFiles
+func releasePIDFiles(repos []string) {
+	if pidFile == "" {
+		return
+	}
+	
+	for _, repo := range repos {
+		path := filepath.Join(repo, pidFile)
+		data, err := os.ReadFile(path)
+		if err == nil && strings.TrimSpace(string(data)) == strconv.Itoa(os.Getpid()) {
+			os.Remove(path)
+		}
+	}
+}
+
+// isProcessRunning checks if pid refers to a live process
+func isProcessRunning(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	
+	// Signal 0 only checks for existence; EPERM means it exists but belongs to someone else
+	err = process.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
\ No newline at end of file
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// profileSection is the git config section holding option profiles, one subsection per profile:
+//
+//	[git-air-profile "personal"]
+//		offline-mode = true
+const profileSection = "git-air-profile"
+
+// profileSetting is one option of a profile
+type profileSetting struct {
+	name  string
+	value string
+}
+
+// applyProfile sets each option of fs that neither the command line nor a GIT_AIR_* variable
+// gave from the named profile in git config (usually ~/.gitconfig). An unknown profile is an
+// error listing the ones that exist.
+func applyProfile(fs *flag.FlagSet, name string) error {
+	// Exits 1 when no profile is set up
+	output, _ := exec.Command("git", "config", "--get-regexp", `^`+profileSection+`\.`).Output()
+	profiles := parseProfiles(string(output))
+	settings, ok := profiles[name]
+	if !ok {
+		var names []string
+		for profile := range profiles {
+			names = append(names, profile)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return fmt.Errorf("no profile %q, git config has no [%s \"<name>\"] sections", name, profileSection)
+		}
+		return fmt.Errorf("no profile %q, available: %s", name, strings.Join(names, ", "))
+	}
+	
+	given := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { given[f.Name] = true })
+	var errs []error
+	for _, setting := range settings {
+		f := fs.Lookup(setting.name)
+		if f == nil {
+			errs = append(errs, fmt.Errorf("profile %s: unknown option -%s", name, setting.name))
+			continue
+		}
+		if _, inEnv := os.LookupEnv(flagEnvName(f.Name)); given[f.Name] || inEnv {
+			continue
+		}
+		if err := f.Value.Set(setting.value); err != nil {
+			errs = append(errs, fmt.Errorf("profile %s: -%s %q: %v", name, setting.name, setting.value, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// parseProfiles groups "git config --get-regexp" output by profile, keeping the options in order.
+// Profile names may contain dots, so the option is what follows the last one.
+func parseProfiles(output string) map[string][]profileSetting {
+	profiles := map[string][]profileSetting{}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		key, value, _ := strings.Cut(line, " ")
+		rest, ok := strings.CutPrefix(key, profileSection+".")
+		dot := strings.LastIndex(rest, ".")
+		if !ok || dot <= 0 {
+			continue
+		}
+		profile := rest[:dot]
+		profiles[profile] = append(profiles[profile], profileSetting{name: rest[dot+1:], value: value})
+	}
+	return profiles
+}
\ No newline at end of file
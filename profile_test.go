@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestApplyProfile(t *testing.T) {
+	global := filepath.Join(t.TempDir(), "gitconfig")
+	os.WriteFile(global, []byte(`[git-air-profile "work"]
+	debounce-window = 5s
+[git-air-profile "personal"]
+	offline-mode = true
+	debounce-window = 1m
+[git-air-profile "broken"]
+	no-such-option = 1
+`), 0644)
+	t.Setenv("GIT_CONFIG_GLOBAL", global)
+	
+	newFlags := func(args ...string) (*flag.FlagSet, *bool, *time.Duration) {
+		fs := flag.NewFlagSet("git-air", flag.ContinueOnError)
+		offline := fs.Bool("offline-mode", false, "")
+		window := fs.Duration("debounce-window", 2*time.Second, "")
+		fs.Parse(args)
+		return fs, offline, window
+	}
+	
+	fs, offline, window := newFlags()
+	if err := applyProfile(fs, "personal"); err != nil {
+		t.Fatal(err)
+	}
+	if !*offline || *window != time.Minute {
+		t.Errorf("personal: -offline-mode %v -debounce-window %s, want true, 1m", *offline, *window)
+	}
+	
+	fs, offline, window = newFlags("-debounce-window", "10s")
+	if err := applyProfile(fs, "work"); err != nil {
+		t.Fatal(err)
+	}
+	if *offline || *window != 10*time.Second {
+		t.Errorf("work: -offline-mode %v -debounce-window %s, want false and the command line's 10s", *offline, *window)
+	}
+	
+	t.Setenv("GIT_AIR_OFFLINE_MODE", "false")
+	fs, offline, _ = newFlags()
+	if err := applyProfile(fs, "personal"); err != nil || *offline {
+		t.Errorf("personal with $GIT_AIR_OFFLINE_MODE=false: -offline-mode %v, %v, want the environment to win", *offline, err)
+	}
+	
+	fs, _, _ = newFlags()
+	if err := applyProfile(fs, "home"); err == nil || !strings.Contains(err.Error(), "available: broken, personal, work") {
+		t.Errorf("applyProfile(home) = %v, want an error listing the profiles", err)
+	}
+	if err := applyProfile(fs, "broken"); err == nil || !strings.Contains(err.Error(), "-no-such-option") {
+		t.Errorf("applyProfile(broken) = %v, want the unknown option reported", err)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// semverPattern matches MAJOR.MINOR.PATCH with optional pre-release and build parts
+var semverPattern = regexp.MustCompile(`^\d+\.\d+\.\d+([-+][0-9A-Za-z.+-]+)?$`)
+
+// versionFileChanged reports whether -version-file is among the staged changes
+func versionFileChanged() bool {
+	if versionFile == "" {
+		return false
+	}
+	for _, file := range stagedFiles() {
+		if file == filepath.ToSlash(versionFile) {
+			return true
+		}
+	}
+	return false
+}
+
+// versionFileChangedSince reports whether the commits of the repo at repoPath after rev changed -version-file
+func versionFileChangedSince(repoPath, rev string) bool {
+	if versionFile == "" {
+		return false
+	}
+	return gitIn(repoPath, "diff", "--quiet", rev, "HEAD", "--", versionFile).Run() != nil
+}
+
+// parseVersionFromFile reads a version from package.json's "version" field, pyproject.toml's
+// version in [project] or [tool.poetry], or the first line of any other file, e.g. VERSION
+func parseVersionFromFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	
+	version := ""
+	switch filepath.Base(path) {
+	case "package.json":
+		var pkg struct {
+			Version string `json:"version"`
+		}
+		if err := json.Unmarshal(data, &pkg); err != nil {
+			return "", fmt.Errorf("%s: %v", path, err)
+		}
+		version = pkg.Version
+	case "pyproject.toml":
+		version = pyprojectVersion(string(data))
+	default:
+		version, _, _ = strings.Cut(string(data), "\n")
+	}
+	
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if !semverPattern.MatchString(version) {
+		return "", fmt.Errorf("%s: %q is not a semantic version", path, version)
+	}
+	return version, nil
+}
+
+// pyprojectVersion finds version = "..." in the [project] or [tool.poetry] table
+func pyprojectVersion(toml string) string {
+	section := ""
+	scanner := bufio.NewScanner(strings.NewReader(toml))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			section = strings.Trim(line, "[] ")
+			continue
+		}
+		if section != "project" && section != "tool.poetry" {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok && strings.TrimSpace(key) == "version" {
+			return strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+	return ""
+}
+
+// tagRelease creates the annotated tag v<version> on HEAD, GPG-signed when sign is set, and
+// pushes it to every remote. An existing tag is left alone.
+func tagRelease(version string, sign bool) error {
+	name := "v" + version
+	if exec.Command("git", "rev-parse", "-q", "--verify", "refs/tags/"+name).Run() == nil {
+		return fmt.Errorf("tag %s already exists", name)
+	}
+	
+	args := []string{"tag", "-a"}
+	if sign && gpgSigningKey != "" {
+		args = append(args, "-u", gpgSigningKey)
+	} else if sign {
+		args = append(args, "-s")
+	}
+	args = append(args, name, "-m", "Release "+name)
+	if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("git tag %s: %s", name, gitErrorLine(output, err))
+	}
+	
+	fmt.Printf("  🏷️  Tagged release %s\n", name)
+	if config, _ := loadRepoConfig(); !config.autoPush {
+		return nil // Pushing the tag would publish the commit
+	}
+	for _, remote := range getRemotes() {
+		if output, err := runRemote(remote, "push", remote, "refs/tags/"+name); err != nil {
+			fmt.Printf("  ⚠️  Pushing %s to %s: %s\n", name, remote, gitErrorLine(output, err))
+		}
+	}
+	return nil
+}
+
+// tagReleaseFromVersionFile tags the release named in -version-file after a commit that changed it
+func tagReleaseFromVersionFile() {
+	version, err := parseVersionFromFile(versionFile)
+	if err != nil {
+		fmt.Printf("  ⚠️  Not tagging a release: %v\n", err)
+		return
+	}
+	if err := tagRelease(version, gpgSign); err != nil {
+		fmt.Printf("  ⚠️  Not tagging a release: %v\n", err)
+	}
+}
\ No newline at end of file
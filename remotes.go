@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// remoteSpec is a named remote URL, as given to -initial-remotes or POST /remotes
+type remoteSpec struct {
+	Repo string `json:"repo,omitempty"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// validateRemoteURL accepts SSH (user@host:path, ssh://), HTTP(S), git:// and file:// URLs and existing local paths
+func validateRemoteURL(url string) error {
+	for _, scheme := range []string{"ssh://", "git+ssh://", "https://", "http://", "git://", "file://"} {
+		if strings.HasPrefix(url, scheme) {
+			if len(url) == len(scheme) {
+				return fmt.Errorf("remote URL %q has no host or path", url)
+			}
+			return nil
+		}
+	}
+	if isSSHURL(url) {
+		return nil
+	}
+	if _, err := os.Stat(url); err == nil {
+		return nil
+	}
+	return fmt.Errorf("remote URL %q is not an SSH, HTTP(S), git:// or file:// URL or an existing path", url)
+}
+
+// addRemote adds a remote to the repo at repoPath and fetches from it to prove it works,
+// removing it again if the fetch fails
+func addRemote(repoPath, name, url string) error {
+	if name == "" || strings.ContainsAny(name, " \t/") {
+		return fmt.Errorf("invalid remote name %q", name)
+	}
+	if err := validateRemoteURL(url); err != nil {
+		return err
+	}
+	
+	if output, err := gitIn(repoPath, "remote", "add", name, url).CombinedOutput(); err != nil {
+		return fmt.Errorf("git remote add: %s", gitErrorLine(output, err))
+	}
+	if offlineMode {
+		return nil
+	}
+	
+	if output, err := runRemoteIn(repoPath, name, "fetch", name); err != nil {
+		removeRemote(repoPath, name)
+		return fmt.Errorf("fetch from %s failed, remote not added: %s", name, gitErrorLine(output, err))
+	}
+	return nil
+}
+
+// removeRemote removes a remote from the repo at repoPath
+func removeRemote(repoPath, name string) error {
+	if output, err := gitIn(repoPath, "remote", "remove", name).CombinedOutput(); err != nil {
+		return fmt.Errorf("git remote remove: %s", gitErrorLine(output, err))
+	}
+	return nil
+}
+
+// gitIn builds a git command that runs in repoPath without changing the process directory
+func gitIn(repoPath string, args ...string) *exec.Cmd {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoPath
+	return cmd
+}
+
+// parseRemoteSpecs parses comma-separated name=url pairs
+func parseRemoteSpecs(value string) ([]remoteSpec, error) {
+	var specs []remoteSpec
+	for _, pair := range splitList(value) {
+		name, url, ok := strings.Cut(pair, "=")
+		if !ok || name == "" || url == "" {
+			return nil, fmt.Errorf("%q must look like name=url", pair)
+		}
+		specs = append(specs, remoteSpec{Name: name, URL: url})
+	}
+	return specs, nil
+}
+
+// ensureInitialRemotes adds every -initial-remotes entry that a repository is missing
+func ensureInitialRemotes(repos []string) {
+	for _, repo := range repos {
+		existing, _ := gitIn(repo, "remote").Output()
+		for _, spec := range initialRemotes {
+			if containsField(string(existing), spec.Name) {
+				continue
+			}
+			if err := addRemote(repo, spec.Name, spec.URL); err != nil {
+				fmt.Printf("  ⚠️  %s: %v\n", filepath.Base(repo), err)
+				continue
+			}
+			fmt.Printf("  🔗 %s: Added remote %s (%s)\n", filepath.Base(repo), spec.Name, spec.URL)
+		}
+	}
+}
+
+// containsField reports whether text has a whitespace-separated field equal to value
+func containsField(text, value string) bool {
+	for _, field := range strings.Fields(text) {
+		if field == value {
+			return true
+		}
+	}
+	return false
+}
+
+// remotesHandler serves POST /remotes with {"repo", "name", "url"} and DELETE /remotes/<name>?repo=<repo>
+func remotesHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		var spec remoteSpec
+		if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+			http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		repoPath, ok := findRepo(spec.Repo)
+		if !ok {
+			http.Error(w, "unknown repository "+spec.Repo, http.StatusNotFound)
+			return
+		}
+		if err := addRemote(repoPath, spec.Name, spec.URL); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		spec.Repo = repoPath
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		writeJSON(w, spec)
+	
+	case http.MethodDelete:
+		name := strings.TrimPrefix(r.URL.Path, "/remotes/")
+		repoPath, ok := findRepo(r.URL.Query().Get("repo"))
+		if !ok {
+			http.Error(w, "unknown repository "+r.URL.Query().Get("repo"), http.StatusNotFound)
+			return
+		}
+		if err := removeRemote(repoPath, name); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// repoConfig holds the settings a repository can override in its git config, e.g.
+// "git config git-air.autoPush false" for a repo whose commits must stay on this machine
+type repoConfig struct {
+	autoCommit     bool
+	autoPush       bool
+	autoPull       bool
+	debounceWindow time.Duration
+}
+
+// loadRepoConfig reads the git-air.* settings of the current repo over the command line ones.
+// git config also reads ~/.gitconfig and the files it includes, so settings there apply to every
+// repo. They are read on every pass, so changes apply without a restart; invalid values are
+// reported and leave the command line setting in place.
+func loadRepoConfig() (repoConfig, error) {
+	config := repoConfig{autoCommit: true, autoPush: true, autoPull: true, debounceWindow: debounceWindow}
+	// Exits 1 when no git-air.* key is set
+	output, _ := exec.Command("git", "config", "--get-regexp", `^git-air\.`).Output()
+	return config, config.apply(string(output))
+}
+
+// apply parses "git config --get-regexp" output, one "<key> <value>" per line. Keys are
+// case-insensitive and later lines win, as in git.
+func (c *repoConfig) apply(output string) error {
+	var errs []error
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		key, value, _ := strings.Cut(line, " ")
+		var err error
+		switch strings.ToLower(key) {
+		case "git-air.autocommit":
+			err = parseGitBool(value, &c.autoCommit)
+		case "git-air.autopush":
+			err = parseGitBool(value, &c.autoPush)
+		case "git-air.autopull":
+			err = parseGitBool(value, &c.autoPull)
+		case "git-air.debouncewindow":
+			window, parseErr := time.ParseDuration(value)
+			if parseErr == nil && window < 0 {
+				parseErr = fmt.Errorf("must be 0 or greater")
+			}
+			if err = parseErr; err == nil {
+				c.debounceWindow = window
+			}
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s %q: %v", key, value, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// parseGitBool parses a git config boolean into dst; a key without a value means true
+func parseGitBool(value string, dst *bool) error {
+	switch strings.ToLower(value) {
+	case "", "true", "yes", "on", "1":
+		*dst = true
+	case "false", "no", "off", "0":
+		*dst = false
+	default:
+		return fmt.Errorf("not a boolean")
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRepoConfigApply(t *testing.T) {
+	defaults := repoConfig{autoCommit: true, autoPush: true, autoPull: true, debounceWindow: 2 * time.Second}
+	tests := []struct {
+		name    string
+		output  string
+		want    repoConfig
+		wantErr bool
+	}{
+		{"nothing set", "", defaults, false},
+		{"push disabled", "git-air.autopush false\n", repoConfig{true, false, true, 2 * time.Second}, false},
+		{"git boolean spellings", "git-air.autocommit no\ngit-air.autopull off\n", repoConfig{false, true, false, 2 * time.Second}, false},
+		{"key without value", "git-air.autopush false\ngit-air.autopush\n", defaults, false},
+		{"later lines win", "git-air.debouncewindow 10s\ngit-air.debouncewindow 1m\n", repoConfig{true, true, true, time.Minute}, false},
+		{"other git-air keys", "git-air.tag critical\n", defaults, false},
+		{"invalid boolean", "git-air.autopush sometimes\n", defaults, true},
+		{"invalid duration", "git-air.debouncewindow soon\n", defaults, true},
+		{"negative duration", "git-air.debouncewindow -1s\n", defaults, true},
+	}
+	for _, tt := range tests {
+		got := defaults
+		err := got.apply(tt.output)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("%s: apply() = %+v, %v, want %+v, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestLoadRepoConfig(t *testing.T) {
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(t.TempDir(), "gitconfig"))
+	dir := newTestRepo(t, filepath.Join(t.TempDir(), "secrets"))
+	runTestGit(t, dir, "config", "git-air.autoPush", "false")
+	runTestGit(t, dir, "config", "git-air.debounceWindow", "30s")
+	oldDir, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(oldDir)
+	
+	oldWindow := debounceWindow
+	debounceWindow = 2 * time.Second
+	defer func() { debounceWindow = oldWindow }()
+	
+	config, err := loadRepoConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (repoConfig{autoCommit: true, autoPush: false, autoPull: true, debounceWindow: 30 * time.Second}); config != want {
+		t.Errorf("loadRepoConfig() = %+v, want %+v", config, want)
+	}
+}
+
+func TestLoadRepoConfigGroups(t *testing.T) {
+	root := t.TempDir()
+	backend := newTestRepo(t, filepath.Join(root, "backend", "api"))
+	frontend := newTestRepo(t, filepath.Join(root, "frontend", "web"))
+	
+	// Group settings come from a file ~/.gitconfig includes for every repo below backend/
+	group := filepath.Join(root, "backend.gitconfig")
+	os.WriteFile(group, []byte("[git-air]\n\tautoPush = false\n\tdebounceWindow = 1m\n"), 0644)
+	global := filepath.Join(root, "gitconfig")
+	os.WriteFile(global, []byte("[includeIf \"gitdir:"+filepath.Join(root, "backend")+"/\"]\n\tpath = "+group+"\n"), 0644)
+	t.Setenv("GIT_CONFIG_GLOBAL", global)
+	
+	oldDir, _ := os.Getwd()
+	defer os.Chdir(oldDir)
+	oldWindow := debounceWindow
+	debounceWindow = 2 * time.Second
+	defer func() { debounceWindow = oldWindow }()
+	
+	tests := []struct {
+		repo string
+		want repoConfig
+	}{
+		{backend, repoConfig{autoCommit: true, autoPush: false, autoPull: true, debounceWindow: time.Minute}},
+		{frontend, repoConfig{autoCommit: true, autoPush: true, autoPull: true, debounceWindow: 2 * time.Second}},
+	}
+	for _, tt := range tests {
+		os.Chdir(tt.repo)
+		if config, err := loadRepoConfig(); config != tt.want || err != nil {
+			t.Errorf("%s: loadRepoConfig() = %+v, %v, want %+v", filepath.Base(tt.repo), config, err, tt.want)
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+)
+
+// gitDirSize adds up the sizes of the files in the .git directory of the repository at repoPath
+func gitDirSize(repoPath string) int64 {
+	var size int64
+	filepath.WalkDir(filepath.Join(repoPath, ".git"), func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Skip unreadable entries
+		}
+		if entry.Type().IsRegular() {
+			if info, err := entry.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
+
+// checkRepoSizes measures newly discovered repositories and marks those over -max-repo-size-mb
+// as too large to sync. Sizes are only taken at discovery, since walking .git is expensive.
+func checkRepoSizes(repos []string) {
+	if maxRepoSizeMB <= 0 {
+		return
+	}
+	
+	limit := maxRepoSizeMB * 1024 * 1024
+	for _, repo := range repos {
+		absPath, err := filepath.Abs(repo)
+		if err != nil {
+			continue
+		}
+		size := gitDirSize(absPath)
+		state.update(absPath, func(status *repoStatus) {
+			status.SizeBytes = size
+			status.Large = size > limit
+		})
+		if size > limit {
+			fmt.Printf("  🐘 Skipping %s: size %dMB exceeds limit %dMB\n", repo, size/(1024*1024), maxRepoSizeMB)
+		}
+	}
+}
+
+// isTooLarge reports whether the current repo exceeded -max-repo-size-mb when it was discovered
+func isTooLarge() bool {
+	large := false
+	state.update(getCurrentDir(), func(repo *repoStatus) { large = repo.Large })
+	return large
+}
\ No newline at end of file
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// resetResult records what resetToRemote replaced, so the work can be recovered
+type resetResult struct {
+	Repo         string `json:"repo"`
+	ResetTo      string `json:"resetTo"`
+	PreviousHead string `json:"previousHead"`
+	StashRef     string `json:"stashRef,omitempty"`
+}
+
+// resetToRemote recovers the current repo from a stuck state by aborting any half-done rebase or
+// merge, stashing uncommitted changes and hard-resetting to remote/branch. The previous HEAD and
+// the stash SHA are reported and logged; the stash also becomes the repo's pendingStash.
+func resetToRemote(remote, branch string) (resetResult, error) {
+	result := resetResult{Repo: getCurrentDir(), ResetTo: remote + "/" + branch, PreviousHead: getHeadSHA()}
+	if output, err := runRemote(remote, "fetch", remote); err != nil {
+		return result, fmt.Errorf("fetching %s: %s", remote, gitErrorLine(output, err))
+	}
+	
+	abortInProgress()
+	if hasChanges() {
+		before := stashHead()
+		cmd := exec.Command("git", "stash", "push", "--include-untracked", "-m", "git-air hard-reset backup")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return result, fmt.Errorf("backing up local changes, not resetting: %s", gitErrorLine(output, err))
+		}
+		// An older stash of the user's is not our backup
+		if ref := stashHead(); ref != before {
+			result.StashRef = ref
+			state.update(result.Repo, func(repo *repoStatus) { repo.PendingStash = result.StashRef })
+		}
+	}
+	
+	cmd := exec.Command("git", "reset", "--hard", result.ResetTo)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return result, fmt.Errorf("git reset --hard %s: %s", result.ResetTo, gitErrorLine(output, err))
+	}
+	
+	fmt.Printf("  🧯 %s: Reset to %s (previous HEAD %.7s)\n", filepath.Base(result.Repo), result.ResetTo, result.PreviousHead)
+	if result.StashRef != "" {
+		fmt.Printf("  🧯 Local changes backed up, restore with: git stash apply %s\n", result.StashRef)
+	}
+	return result, nil
+}
+
+// abortInProgress abandons a half-done rebase, merge or cherry-pick; each fails harmlessly
+// when nothing is in progress
+func abortInProgress() {
+	exec.Command("git", "rebase", "--abort").Run()
+	exec.Command("git", "merge", "--abort").Run()
+	exec.Command("git", "cherry-pick", "--abort").Run()
+}
+
+// resetCurrentRepo resets the current repo to its primary remote's copy of the current branch
+func resetCurrentRepo() (resetResult, error) {
+	remote := primaryRemote(getRemotes())
+	if remote == "" {
+		return resetResult{Repo: getCurrentDir()}, fmt.Errorf("no remote to reset to")
+	}
+	// A rebase leaves HEAD detached, so the branch is only known once it is aborted
+	abortInProgress()
+	return resetToRemote(remote, getCurrentBranch())
+}
+
+// resetHandler serves POST /reset/<repo>. The body must be {"confirm": "RESET"}.
+func resetHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	
+	name, err := url.PathUnescape(strings.TrimPrefix(r.URL.EscapedPath(), "/reset/"))
+	if err != nil {
+		http.Error(w, "invalid repository "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	repoPath, ok := findRepo(name)
+	if !ok {
+		http.Error(w, "unknown repository "+name, http.StatusNotFound)
+		return
+	}
+	var req struct {
+		Confirm string `json:"confirm"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Confirm != "RESET" {
+		http.Error(w, `a hard reset discards local commits, send {"confirm": "RESET"} to proceed`, http.StatusBadRequest)
+		return
+	}
+	
+	syncMu.Lock()
+	defer syncMu.Unlock()
+	oldDir, _ := os.Getwd()
+	os.Chdir(repoPath)
+	defer os.Chdir(oldDir)
+	
+	fmt.Printf("🧯 Hard reset of %s requested\n", repoPath)
+	result, err := resetCurrentRepo()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result)
+}
+
+// runForceReset implements "git-air -force-reset [repo]"
+func runForceReset(repo string) {
+	if repo != "" {
+		if err := os.Chdir(repo); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if _, err := resetCurrentRepo(); err != nil {
+		log.Fatalf("Not reset: %v", err)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// repoInfo is what git-air works out about a repository by walking its working tree
+type repoInfo struct {
+	monorepo bool
+}
+
+// cachedRepo is a repoInfo along with what it was computed from
+type cachedRepo struct {
+	info          repoInfo
+	scannedAt     time.Time
+	gitDirModTime time.Time
+}
+
+var (
+	scanCache   = map[string]cachedRepo{}
+	scanCacheMu sync.Mutex
+)
+
+// isMonorepo checks if a repository contains submodules or nested repos. The answer is cached
+// until the repo's .git directory changes or -scan-cache-ttl passes, since finding nested repos
+// walks the whole working tree.
+func isMonorepo(repoPath string) bool {
+	return analyzeRepo(repoPath).monorepo
+}
+
+// analyzeRepo returns the cached repoInfo of repoPath, recomputing it when it is stale
+func analyzeRepo(repoPath string) repoInfo {
+	absPath, err := filepath.Abs(repoPath)
+	if err != nil {
+		absPath = repoPath
+	}
+	gitDir, err := os.Stat(filepath.Join(absPath, ".git"))
+	if err != nil || scanCacheTTL <= 0 {
+		return repoInfo{monorepo: detectMonorepo(absPath)}
+	}
+	
+	scanCacheMu.Lock()
+	cached, ok := scanCache[absPath]
+	scanCacheMu.Unlock()
+	if ok && cached.gitDirModTime.Equal(gitDir.ModTime()) && time.Since(cached.scannedAt) < scanCacheTTL {
+		return cached.info
+	}
+	
+	info := repoInfo{monorepo: detectMonorepo(absPath)}
+	scanCacheMu.Lock()
+	scanCache[absPath] = cachedRepo{info: info, scannedAt: time.Now(), gitDirModTime: gitDir.ModTime()}
+	scanCacheMu.Unlock()
+	return info
+}
+
+// forgetRepoInfo drops the cached analysis of a removed repository
+func forgetRepoInfo(repoPath string) {
+	scanCacheMu.Lock()
+	defer scanCacheMu.Unlock()
+	delete(scanCache, repoPath)
+}
\ No newline at end of file
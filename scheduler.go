@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// commitSchedule decides when the next auto-commit pass is due
+type commitSchedule interface {
+	next(after time.Time) time.Time
+}
+
+// newCommitSchedule returns a cron schedule for expr, or a fixed interval when expr is empty
+func newCommitSchedule(expr string, interval time.Duration) (commitSchedule, error) {
+	if expr == "" {
+		return intervalSchedule{every: interval}, nil
+	}
+	return parseCron(expr)
+}
+
+// intervalSchedule runs a pass every fixed duration
+type intervalSchedule struct {
+	every time.Duration
+}
+
+func (s intervalSchedule) next(after time.Time) time.Time {
+	return after.Add(s.every)
+}
+
+// cronSchedule is a standard five-field cron expression: minute hour day-of-month month day-of-week
+type cronSchedule struct {
+	minutes, hours, days, months, weekdays map[int]bool
+	anyDay, anyWeekday                     bool
+}
+
+// parseCron parses expressions such as "0 17 * * 1-5" or "*/15 9-18 * * *"
+func parseCron(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields", expr)
+	}
+	
+	s := &cronSchedule{anyDay: fields[2] == "*", anyWeekday: fields[4] == "*"}
+	var err error
+	if s.minutes, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, err
+	}
+	if s.hours, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, err
+	}
+	if s.days, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, err
+	}
+	if s.months, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, err
+	}
+	if s.weekdays, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, err
+	}
+	if s.weekdays[7] {
+		s.weekdays[0] = true // Both 0 and 7 mean Sunday
+	}
+	return s, nil
+}
+
+// parseCronField expands one field made of "*", "a", "a-b" and "/step" parts separated by commas
+func parseCronField(field string, min, max int) (map[int]bool, error) {
+	values := map[int]bool{}
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if rangePart, stepPart, ok := strings.Cut(part, "/"); ok {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid step in cron field %q", field)
+			}
+			part, step = rangePart, n
+		}
+		
+		low, high := min, max
+		if part != "*" {
+			lowPart, highPart, isRange := strings.Cut(part, "-")
+			var err error
+			if low, err = strconv.Atoi(lowPart); err != nil {
+				return nil, fmt.Errorf("invalid cron field %q", field)
+			}
+			high = low
+			if isRange {
+				if high, err = strconv.Atoi(highPart); err != nil {
+					return nil, fmt.Errorf("invalid cron field %q", field)
+				}
+			} else if step > 1 {
+				high = max // "5/10" means every 10 starting at 5
+			}
+		}
+		if low < min || high > max || low > high {
+			return nil, fmt.Errorf("cron field %q out of range %d-%d", field, min, max)
+		}
+		
+		for value := low; value <= high; value += step {
+			values[value] = true
+		}
+	}
+	return values, nil
+}
+
+// next returns the first matching minute after the given time, or the zero time if none within 5 years
+func (s *cronSchedule) next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if !s.months[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.hours[t.Hour()] {
+			// Truncate works in UTC, which is off by the half hour in zones such as Asia/Kolkata
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.minutes[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches follows cron's rule that a restricted day-of-month and day-of-week are OR-ed
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	dayOK := s.days[t.Day()]
+	weekdayOK := s.weekdays[int(t.Weekday())]
+	switch {
+	case s.anyDay && s.anyWeekday:
+		return true
+	case s.anyDay:
+		return weekdayOK
+	case s.anyWeekday:
+		return dayOK
+	default:
+		return dayOK || weekdayOK
+	}
+}
+
+// quietHourRange is a daily window, in minutes after midnight, during which nothing is committed or pushed
+type quietHourRange struct {
+	start, end int
+}
+
+// parseQuietHours parses comma-separated "HH:MM-HH:MM" windows; a window may span midnight, e.g. "22:00-07:00"
+func parseQuietHours(value string) ([]quietHourRange, error) {
+	var ranges []quietHourRange
+	for _, item := range splitList(value) {
+		startPart, endPart, ok := strings.Cut(item, "-")
+		if !ok {
+			return nil, fmt.Errorf("quiet hours %q must look like HH:MM-HH:MM", item)
+		}
+		start, err := parseClock(startPart)
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseClock(endPart)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, quietHourRange{start: start, end: end})
+	}
+	return ranges, nil
+}
+
+// parseClock converts a 24-hour "HH:MM" time into minutes after midnight
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// isQuietHour reports whether now, seen in tz, falls inside any of the ranges.
+// Ranges include their start and exclude their end.
+func isQuietHour(now time.Time, ranges []quietHourRange, tz *time.Location) bool {
+	if len(ranges) == 0 {
+		return false
+	}
+	
+	local := now.In(tz)
+	minute := local.Hour()*60 + local.Minute()
+	for _, r := range ranges {
+		if r.start <= r.end {
+			if minute >= r.start && minute < r.end {
+				return true
+			}
+		} else if minute >= r.start || minute < r.end {
+			return true
+		}
+	}
+	return false
+}
\ No newline at end of file
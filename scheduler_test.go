@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	kolkata := time.FixedZone("IST", 5*3600+30*60)
+	kathmandu := time.FixedZone("NPT", 5*3600+45*60)
+	tests := []struct {
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		{"0 17 * * *", time.Date(2024, 3, 4, 9, 30, 0, 0, time.UTC), time.Date(2024, 3, 4, 17, 0, 0, 0, time.UTC)},
+		{"0 17 * * *", time.Date(2024, 3, 4, 17, 0, 0, 0, time.UTC), time.Date(2024, 3, 5, 17, 0, 0, 0, time.UTC)},
+		{"0 17 * * *", time.Date(2024, 3, 4, 9, 30, 0, 0, kolkata), time.Date(2024, 3, 4, 17, 0, 0, 0, kolkata)},
+		{"15 9 * * *", time.Date(2024, 3, 4, 9, 20, 0, 0, kathmandu), time.Date(2024, 3, 5, 9, 15, 0, 0, kathmandu)},
+		{"*/15 9-18 * * *", time.Date(2024, 3, 4, 9, 7, 30, 0, time.UTC), time.Date(2024, 3, 4, 9, 15, 0, 0, time.UTC)},
+		{"*/15 9-18 * * *", time.Date(2024, 3, 4, 18, 50, 0, 0, kolkata), time.Date(2024, 3, 5, 9, 0, 0, 0, kolkata)},
+		// 2024-03-08 is a Friday, so the next weekday run is Monday
+		{"0 17 * * 1-5", time.Date(2024, 3, 8, 18, 0, 0, 0, time.UTC), time.Date(2024, 3, 11, 17, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 29 2 *", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2028, 2, 29, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		s, err := parseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("parseCron(%q): %v", tt.expr, err)
+		}
+		if got := s.next(tt.after); !got.Equal(tt.want) {
+			t.Errorf("%q.next(%s) = %s, want %s", tt.expr, tt.after, got, tt.want)
+		}
+	}
+}
+
+func TestParseCronRejectsInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"0 17 * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+	} {
+		if _, err := parseCron(expr); err == nil {
+			t.Errorf("parseCron(%q) succeeded, want an error", expr)
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ensureSparseCheckout limits each repository's working tree to -sparse-checkout-paths using
+// cone mode. Files outside those directories are not checked out, so they are never staged either.
+func ensureSparseCheckout(repos []string) {
+	if len(sparsePaths) == 0 {
+		return
+	}
+	
+	for _, repo := range repos {
+		current, err := gitIn(repo, "sparse-checkout", "list").Output()
+		if err == nil && sameLines(string(current), sparsePaths) {
+			continue
+		}
+		
+		args := append([]string{"sparse-checkout", "set", "--cone"}, sparsePaths...)
+		if output, err := gitIn(repo, args...).CombinedOutput(); err != nil {
+			fmt.Printf("  ⚠️  %s: Sparse checkout failed: %s\n", filepath.Base(repo), strings.TrimSpace(string(output)))
+			continue
+		}
+		fmt.Printf("  🌿 %s: Sparse checkout of %s\n", filepath.Base(repo), strings.Join(sparsePaths, ", "))
+	}
+}
+
+// sameLines reports whether output lists exactly paths, one per line, in any order
+func sameLines(output string, paths []string) bool {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) != len(paths) {
+		return false
+	}
+	
+	want := map[string]bool{}
+	for _, path := range paths {
+		want[strings.Trim(path, "/")] = true
+	}
+	for _, line := range lines {
+		if !want[strings.Trim(line, "/")] {
+			return false
+		}
+	}
+	return true
+}
\ No newline at end of file
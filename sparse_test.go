@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestSameLines(t *testing.T) {
+	tests := []struct {
+		output string
+		paths  []string
+		want   bool
+	}{
+		{"", nil, true},
+		{"src\ndocs\n", []string{"src", "docs"}, true},
+		{"docs\nsrc\n", []string{"src", "docs"}, true},
+		{"src\n", []string{"src/"}, true},
+		{"src\n", []string{"/src"}, true},
+		{"src\ndocs\n", []string{"src"}, false},
+		{"src\n", []string{"src", "docs"}, false},
+		{"src\ntests\n", []string{"src", "docs"}, false},
+		// Paths with spaces are one entry each
+		{"design docs\nsrc\n", []string{"design docs", "src"}, true},
+		{"design docs\n", []string{"design", "docs"}, false},
+	}
+	for _, tt := range tests {
+		if got := sameLines(tt.output, tt.paths); got != tt.want {
+			t.Errorf("sameLines(%q, %q) = %v, want %v", tt.output, tt.paths, got, tt.want)
+		}
+	}
+}
\ No newline at end of file
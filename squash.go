@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// squashAutoCommits folds the run of auto-commits at the tip of the current branch that none of
+// remotes has into one commit when there are more than threshold of them. Commits made by hand
+// stop the run, so they are never rewritten.
+func squashAutoCommits(remotes []string, branch string, threshold int) {
+	n := -1
+	for _, remote := range remotes {
+		unpushed, err := getUnpushedCommits(remote, branch)
+		if err != nil {
+			continue // The branch isn't on this remote, so it has none of the commits
+		}
+		run := 0
+		for _, commit := range unpushed {
+			if !isAutoCommit(commit.SHA) {
+				break
+			}
+			run++
+		}
+		if n < 0 || run < n {
+			n = run
+		}
+	}
+	// n stays -1 when nothing was pushed anywhere yet, so there is no base to squash onto
+	if n <= threshold || n < 2 {
+		return
+	}
+	
+	files := countFilesChangedSince("HEAD~" + strconv.Itoa(n))
+	message := fmt.Sprintf("auto commit - %d auto-commits squashed, %d files changed - %s",
+		n, files, time.Now().Format("2006-01-02 15:04:05"))
+	if err := squashLastNCommits(n, message); err != nil {
+		fmt.Printf("  ⚠️  Squashing auto-commits: %v\n", err)
+		return
+	}
+	fmt.Printf("  🗜️  Squashed %d auto-commits into one\n", n)
+}
+
+// squashLastNCommits replaces the last n commits of the current branch with a single commit
+// holding their combined changes, restoring the original commits if that fails
+func squashLastNCommits(n int, message string) error {
+	head := getHeadSHA()
+	base := "HEAD~" + strconv.Itoa(n)
+	cmd := exec.Command("git", "reset", "--soft", base)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git reset --soft %s: %s", base, gitErrorLine(output, err))
+	}
+	
+	cmd = exec.Command("git", commitArgs(message)...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		exec.Command("git", "reset", "--soft", head).Run()
+		return fmt.Errorf("git commit: %s", gitErrorLine(output, err))
+	}
+	return nil
+}
+
+// countFilesChangedSince counts the files that differ between rev and HEAD
+func countFilesChangedSince(rev string) int {
+	output, err := exec.Command("git", "diff", "--name-only", rev, "HEAD").Output()
+	if err != nil || strings.TrimSpace(string(output)) == "" {
+		return 0
+	}
+	return len(strings.Split(strings.TrimSpace(string(output)), "\n"))
+}
\ No newline at end of file
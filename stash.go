@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// stashEntry is one entry of git stash list
+type stashEntry struct {
+	Ref       string    `json:"ref"`
+	SHA       string    `json:"sha"`
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// listStashes returns the stashes of the repository at repoPath, newest first
+func listStashes(repoPath string) ([]stashEntry, error) {
+	output, err := gitIn(repoPath, "stash", "list", "--format=%gd|%H|%ct|%gs").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git stash list in %s: %v", repoPath, err)
+	}
+	
+	entries := []stashEntry{}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line == "" {
+			continue
+		}
+		
+		fields := strings.SplitN(line, "|", 4)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("unexpected git stash list line %q", line)
+		}
+		seconds, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected stash time in %q", line)
+		}
+		entries = append(entries, stashEntry{Ref: fields[0], SHA: fields[1], Message: fields[3], Timestamp: time.Unix(seconds, 0)})
+	}
+	return entries, nil
+}
+
+// applyStash applies the stash ref (stash@{n} or a stash commit SHA) in the repository at repoPath
+// and, once it applied cleanly, drops it from the stash list. A conflicting apply is undone so
+// conflict markers never get auto-committed.
+func applyStash(repoPath, ref string) error {
+	if output, err := gitIn(repoPath, "stash", "apply", ref).CombinedOutput(); err != nil {
+		gitIn(repoPath, "reset", "--merge").Run()
+		return fmt.Errorf("git stash apply %s: %s", ref, strings.TrimSpace(string(output)))
+	}
+	
+	sha, _ := gitIn(repoPath, "rev-parse", ref).Output()
+	entries, _ := listStashes(repoPath)
+	for _, entry := range entries {
+		if entry.SHA == strings.TrimSpace(string(sha)) {
+			gitIn(repoPath, "stash", "drop", entry.Ref).Run()
+			break
+		}
+	}
+	
+	state.update(repoPath, func(repo *repoStatus) {
+		if repo.PendingStash == strings.TrimSpace(string(sha)) {
+			repo.PendingStash = ""
+		}
+	})
+	return nil
+}
+
+// stashApplyHandler serves POST /stash/apply/<ref>?repo=<repo>, restoring a stash kept after a
+// conflicting pop (its SHA is the repo's pendingStash in GET /status)
+func stashApplyHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	
+	ref := strings.TrimPrefix(r.URL.Path, "/stash/apply/")
+	repoPath, ok := findRepo(r.URL.Query().Get("repo"))
+	if !ok {
+		http.Error(w, "unknown repository "+r.URL.Query().Get("repo"), http.StatusNotFound)
+		return
+	}
+	
+	syncMu.Lock()
+	defer syncMu.Unlock()
+	if err := applyStash(repoPath, ref); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	fmt.Printf("📦 Applied stash %s in %s\n", ref, repoPath)
+	w.WriteHeader(http.StatusNoContent)
+}
\ No newline at end of file
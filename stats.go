@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// repoStats counts sync activity for one repository since startup
+type repoStats struct {
+	CommitCount        int64            `json:"commitCount"`
+	PushCount          int64            `json:"pushCount"`
+	PullCount          int64            `json:"pullCount"`
+	PushBytesTotal     int64            `json:"pushBytesTotal"`
+	MirrorPushCount    int64            `json:"mirrorPushCount"`
+	MirrorPushFailures int64            `json:"mirrorPushFailures"`
+	ConflictsAvoided   int64            `json:"conflictsAvoided"`
+	FilesCommitted     int64            `json:"filesCommitted"`
+	PushErrors         map[string]int64 `json:"pushErrors"`
+	PullErrors         map[string]int64 `json:"pullErrors"`
+	FirstSeen          time.Time        `json:"firstSeen"`
+	LastActivity       time.Time        `json:"lastActivity"`
+}
+
+// statsCollector holds repoStats per repository path, shared with the status server
+type statsCollector struct {
+	mu    sync.Mutex
+	repos map[string]*repoStats
+}
+
+var stats = &statsCollector{repos: map[string]*repoStats{}}
+
+// record applies fn to the stats of repoPath and marks it as active now
+func (c *statsCollector) record(repoPath string, fn func(*repoStats)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	
+	now := time.Now()
+	repo, ok := c.repos[repoPath]
+	if !ok {
+		repo = &repoStats{FirstSeen: now, PushErrors: map[string]int64{}, PullErrors: map[string]int64{}}
+		c.repos[repoPath] = repo
+	}
+	fn(repo)
+	repo.LastActivity = now
+}
+
+// snapshot returns a copy of the stats of every repository
+func (c *statsCollector) snapshot() map[string]repoStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	
+	copied := make(map[string]repoStats, len(c.repos))
+	for path, repo := range c.repos {
+		repoCopy := *repo
+		repoCopy.PushErrors = copyCounts(repo.PushErrors)
+		repoCopy.PullErrors = copyCounts(repo.PullErrors)
+		copied[path] = repoCopy
+	}
+	return copied
+}
+
+// countError counts a failed push or pull to remote
+func (c *statsCollector) countError(repoPath, operation, remote string) {
+	c.record(repoPath, func(repo *repoStats) {
+		if operation == "push" {
+			repo.PushErrors[remote]++
+		} else {
+			repo.PullErrors[remote]++
+		}
+	})
+}
+
+func copyCounts(counts map[string]int64) map[string]int64 {
+	copied := make(map[string]int64, len(counts))
+	for key, count := range counts {
+		copied[key] = count
+	}
+	return copied
+}
+
+// writingObjects matches git's progress line, e.g. "Writing objects: 100% (3/3), 1.20 KiB | 1.20 MiB/s, done."
+var writingObjects = regexp.MustCompile(`Writing objects: 100% \(\d+/\d+\), ([\d.]+) (bytes|KiB|MiB|GiB)`)
+
+// parsePushBytes extracts how much data a git push --progress sent, or 0 if nothing was written
+func parsePushBytes(output string) int64 {
+	matches := writingObjects.FindAllStringSubmatch(output, -1)
+	if len(matches) == 0 {
+		return 0
+	}
+	
+	match := matches[len(matches)-1]
+	size, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0
+	}
+	switch match[2] {
+	case "KiB":
+		size *= 1 << 10
+	case "MiB":
+		size *= 1 << 20
+	case "GiB":
+		size *= 1 << 30
+	}
+	return int64(size)
+}
+
+// statsHandler serves GET /stats with the counters of every repository
+func statsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, stats.snapshot())
+}
+
+// printStats fetches /stats from the git-air instance serving addr and prints it as a table
+func printStats(addr string) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(statusURL(addr, "/stats"))
+	if err != nil {
+		log.Fatalf("Fetching stats: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.Fatalf("Fetching stats: %s", resp.Status)
+	}
+	
+	var repos map[string]repoStats
+	if err := json.NewDecoder(resp.Body).Decode(&repos); err != nil {
+		log.Fatalf("Decoding stats: %v", err)
+	}
+	
+	paths := make([]string, 0, len(repos))
+	for path := range repos {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "REPO\tCOMMITS\tPUSHES\tPULLS\tPUSHED\tCONFLICTS AVOIDED\tLAST ACTIVITY")
+	for _, path := range paths {
+		repo := repos[path]
+		fmt.Fprintf(table, "%s\t%d\t%d\t%d\t%s\t%d\t%s\n", filepath.Base(path), repo.CommitCount, repo.PushCount,
+			repo.PullCount, formatBytes(repo.PushBytesTotal), repo.ConflictsAvoided, repo.LastActivity.Format("2006-01-02 15:04"))
+	}
+	table.Flush()
+}
+
+// formatBytes renders a byte count as B, KiB, MiB or GiB
+func formatBytes(n int64) string {
+	units := []string{"B", "KiB", "MiB", "GiB"}
+	size := float64(n)
+	unit := 0
+	for size >= 1024 && unit < len(units)-1 {
+		size /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%d B", n)
+	}
+	return fmt.Sprintf("%.1f %s", size, units[unit])
+}
\ No newline at end of file
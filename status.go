@@ -0,0 +1,525 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// repoStatus is the per-repository sync state served by GET /status
+type repoStatus struct {
+	Repo               string                           `json:"repo"`
+	Branch             string                           `json:"branch"`
+	LastCommitAt       time.Time                        `json:"lastCommitAt"`
+	LastPushAt         time.Time                        `json:"lastPushAt"`
+	LastPushedTo       []string                         `json:"lastPushedTo"`
+	LastPullAt         time.Time                        `json:"lastPullAt"`
+	PendingChanges     bool                             `json:"pendingChanges"`
+	Ahead              int                              `json:"ahead"`
+	Behind             int                              `json:"behind"`
+	UnpushedCommits    int                              `json:"unpushedCommits"`
+	ActiveContributors int                              `json:"activeContributors"`
+	Diverged           bool                             `json:"diverged"`
+	DivergedAt         time.Time                        `json:"divergedAt"`
+	Inactive           bool                             `json:"inactive"`
+	SizeBytes          int64                            `json:"sizeBytes,omitempty"`
+	Large              bool                             `json:"large"`
+	Remotes            []string                         `json:"remotes"`
+	DuplicateRemotes   []string                         `json:"duplicateRemotes"`
+	PendingStash       string                           `json:"pendingStash,omitempty"`
+	PullRequestURL     string                           `json:"pullRequestUrl,omitempty"`
+	UnreachableRemotes map[string]connectivityErrorType `json:"unreachableRemotes,omitempty"`
+	FsckIssues         []fsckIssue                      `json:"fsckIssues,omitempty"`
+	LastFsckAt         time.Time                        `json:"lastFsckAt"`
+	Errors             []string                         `json:"errors"`
+	ErrorCount         int                              `json:"errorCount"`
+}
+
+// serviceState holds sync state for every repository, shared with the status server
+type serviceState struct {
+	mu           sync.RWMutex
+	repos        map[string]*repoStatus
+	reposAdded   int
+	reposRemoved int
+}
+
+var state = &serviceState{repos: map[string]*repoStatus{}}
+
+// update applies fn to the state of repoPath, creating it on first use
+func (s *serviceState) update(repoPath string, fn func(*repoStatus)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	
+	repo, ok := s.repos[repoPath]
+	if !ok {
+		repo = &repoStatus{Repo: repoPath, Errors: []string{}}
+		s.repos[repoPath] = repo
+	}
+	fn(repo)
+}
+
+// forget drops a repository that no longer exists
+func (s *serviceState) forget(repoPath string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.repos, repoPath)
+}
+
+// countScanChanges adds to the repos added/removed totals since startup
+func (s *serviceState) countScanChanges(added, removed int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reposAdded += added
+	s.reposRemoved += removed
+}
+
+// snapshot returns a copy of every repo state, sorted by path
+func (s *serviceState) snapshot() []repoStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	
+	repos := make([]repoStatus, 0, len(s.repos))
+	for _, repo := range s.repos {
+		copied := *repo
+		copied.Errors = append([]string{}, repo.Errors...)
+		if repo.Remotes != nil {
+			copied.Remotes = append([]string{}, repo.Remotes...)
+		}
+		if repo.UnreachableRemotes != nil {
+			copied.UnreachableRemotes = map[string]connectivityErrorType{}
+			for remote, errorType := range repo.UnreachableRemotes {
+				copied.UnreachableRemotes[remote] = errorType
+			}
+		}
+		repos = append(repos, copied)
+	}
+	sort.Slice(repos, func(i, j int) bool { return repos[i].Repo < repos[j].Repo })
+	return repos
+}
+
+// recordError keeps the 10 most recent errors for a repository and counts all of them
+func recordError(repoPath, message string) {
+	state.update(repoPath, func(repo *repoStatus) {
+		entry := time.Now().Format("2006-01-02 15:04:05") + " " + message
+		repo.ErrorCount++
+		repo.Errors = append(repo.Errors, entry)
+		if len(repo.Errors) > 10 {
+			repo.Errors = repo.Errors[len(repo.Errors)-10:]
+		}
+	})
+}
+
+// refreshRepoState records the branch, pending changes and divergence of the current repo
+func refreshRepoState() {
+	branch := getCurrentBranch()
+	if detached, _ := isDetachedHead(); detached {
+		branch = "" // Report no branch rather than the default branch getCurrentBranch falls back to
+	}
+	pending := hasChanges()
+	remotes, duplicates := dedupRemotes(getRawRemotes())
+	ahead, behind, _ := getAheadBehind(primaryRemote(remotes), branch)
+	unpushed, _ := getUnpushedCommits(primaryRemote(remotes), branch)
+	
+	repoPath := getCurrentDir()
+	active := countActiveContributors(repoPath)
+	previous := 0
+	state.update(repoPath, func(repo *repoStatus) {
+		previous = repo.UnpushedCommits
+		repo.Branch = branch
+		repo.PendingChanges = pending
+		repo.Ahead = ahead
+		repo.Behind = behind
+		repo.UnpushedCommits = len(unpushed)
+		repo.Remotes = append([]string{}, remotes...)
+		repo.ActiveContributors = active
+		repo.DuplicateRemotes = duplicates
+	})
+	
+	// Warn once when the backlog crosses the limit, e.g. while the remote is unreachable
+	if maxUnpushed > 0 && len(unpushed) > maxUnpushed && previous <= maxUnpushed {
+		fmt.Printf("  ⚠️  %s: %d commits not pushed to %s (oldest from %s), the remote is falling behind\n",
+			filepath.Base(repoPath), len(unpushed), primaryRemote(remotes),
+			unpushed[len(unpushed)-1].Timestamp.Format("2006-01-02 15:04"))
+	}
+}
+
+// pauseControl suspends auto-commits and pulls, indefinitely or until a deadline
+type pauseControl struct {
+	mu     sync.Mutex
+	paused bool
+	until  time.Time
+}
+
+var pause = &pauseControl{}
+
+// set pauses for d, or until resumed when d is 0
+func (p *pauseControl) set(d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paused = true
+	p.until = time.Time{}
+	if d > 0 {
+		p.until = time.Now().Add(d)
+	}
+}
+
+func (p *pauseControl) resume() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paused = false
+	p.until = time.Time{}
+}
+
+// state reports whether sync is paused and until when (zero for indefinitely), resuming expired pauses
+func (p *pauseControl) state() (bool, time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.paused && !p.until.IsZero() && time.Now().After(p.until) {
+		p.paused = false
+		p.until = time.Time{}
+		fmt.Println("▶️  Pause expired, resuming auto-sync")
+	}
+	return p.paused, p.until
+}
+
+func (p *pauseControl) isPaused() bool {
+	paused, _ := p.state()
+	return paused
+}
+
+// startStatusServer serves the status API on addr in the background
+func startStatusServer(addr string) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatalf("Status server: %v", err)
+	}
+	
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", statusHandler)
+	mux.HandleFunc("/health", healthHandler)
+	mux.HandleFunc("/status/log/", repoLogHandler)
+	mux.HandleFunc("/status/orphans", orphansHandler)
+	mux.HandleFunc("/contributors/", contributorsHandler)
+	mux.HandleFunc("/branches/", branchesHandler)
+	mux.HandleFunc("/history/", fileHistoryHandler)
+	mux.HandleFunc("/blame/", fileHistoryHandler)
+	mux.HandleFunc("/stats", statsHandler)
+	mux.HandleFunc("/remotes", remotesHandler)
+	mux.HandleFunc("/remotes/", remotesHandler)
+	mux.HandleFunc("/pause", pauseHandler)
+	mux.HandleFunc("/resume", resumeHandler)
+	mux.HandleFunc("/stash/apply/", stashApplyHandler)
+	mux.HandleFunc("/cherry-pick", cherryPickHandler)
+	mux.HandleFunc("/reset/", resetHandler)
+	
+	// /sync/<repo> carries URL-encoded paths, which ServeMux would "clean" into a redirect
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := authorizeStatusRequest(r, statusToken); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, "/sync/tag/") {
+			tagSyncHandler(w, r)
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, "/sync/") {
+			syncHandler(w, r)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+	
+	fmt.Printf("📊 Status API listening on %s\n", listener.Addr())
+	go func() {
+		if err := http.Serve(listener, handler); err != nil {
+			log.Printf("Status server stopped: %v", err)
+		}
+	}()
+}
+
+// statusHandler renders the state of every repository as JSON
+func statusHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	
+	state.mu.RLock()
+	added, removed := state.reposAdded, state.reposRemoved
+	state.mu.RUnlock()
+	
+	paused, pausedUntil := pause.state()
+	writeJSON(w, map[string]interface{}{
+		"paused":       paused,
+		"pausedUntil":  pausedUntil,
+		"repos":        state.snapshot(),
+		"reposAdded":   added,
+		"reposRemoved": removed,
+	})
+}
+
+// orphanRepo is a repository whose auto-commits reach no remote
+type orphanRepo struct {
+	Repo               string                           `json:"repo"`
+	Reason             string                           `json:"reason"`
+	UnreachableRemotes map[string]connectivityErrorType `json:"unreachableRemotes,omitempty"`
+}
+
+// findOrphans picks the repos with no remote at all, or whose remotes all failed their last check
+func findOrphans(repos []repoStatus) []orphanRepo {
+	orphans := []orphanRepo{}
+	for _, repo := range repos {
+		if repo.Remotes == nil {
+			continue // Not processed yet
+		}
+		
+		dead := 0
+		for _, remote := range repo.Remotes {
+			if _, ok := repo.UnreachableRemotes[remote]; ok {
+				dead++
+			}
+		}
+		switch {
+		case len(repo.Remotes) == 0:
+			orphans = append(orphans, orphanRepo{Repo: repo.Repo, Reason: "no remotes"})
+		case dead == len(repo.Remotes):
+			orphans = append(orphans, orphanRepo{Repo: repo.Repo, Reason: "all remotes unreachable", UnreachableRemotes: repo.UnreachableRemotes})
+		}
+	}
+	return orphans
+}
+
+// orphansHandler serves GET /status/orphans
+func orphansHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, findOrphans(state.snapshot()))
+}
+
+// pauseHandler serves POST /pause, optionally with ?for=<duration> to resume automatically
+func pauseHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	
+	var d time.Duration
+	if value := r.URL.Query().Get("for"); value != "" {
+		var err error
+		if d, err = time.ParseDuration(value); err != nil || d < 0 {
+			http.Error(w, "invalid duration "+value, http.StatusBadRequest)
+			return
+		}
+	}
+	
+	pause.set(d)
+	if d > 0 {
+		fmt.Printf("⏸️  Auto-sync paused for %s\n", d)
+	} else {
+		fmt.Println("⏸️  Auto-sync paused until resumed")
+	}
+	paused, until := pause.state()
+	writeJSON(w, map[string]interface{}{"paused": paused, "pausedUntil": until})
+}
+
+// resumeHandler serves POST /resume
+func resumeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	
+	pause.resume()
+	fmt.Println("▶️  Auto-sync resumed")
+	writeJSON(w, map[string]interface{}{"paused": false})
+}
+
+// statusURL turns a listen address such as ":8080" into a URL for path on this host
+func statusURL(addr, path string) string {
+	if strings.HasPrefix(addr, ":") {
+		addr = "localhost" + addr
+	}
+	return "http://" + addr + path
+}
+
+// findRepo looks up a known repository by directory name or full path
+func findRepo(name string) (string, bool) {
+	for _, repo := range state.snapshot() {
+		if repo.Repo == name || filepath.Base(repo.Repo) == name {
+			return repo.Repo, true
+		}
+	}
+	return "", false
+}
+
+// writeJSON writes v as an indented JSON response
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
+
+// authorizeStatusRequest lets reads through and requires requests that change state (committing,
+// resetting, adding remotes, ...) to carry token as a bearer token, or to come from this host when
+// there is no token
+func authorizeStatusRequest(r *http.Request, token string) error {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return nil
+	}
+	if token == "" {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if ip := net.ParseIP(host); err != nil || ip == nil || !ip.IsLoopback() {
+			return fmt.Errorf("%s %s is only accepted from localhost without -status-token", r.Method, r.URL.Path)
+		}
+		return nil
+	}
+	given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+		return fmt.Errorf("%s %s needs the -status-token bearer token", r.Method, r.URL.Path)
+	}
+	return nil
+}
+
+// postStatus sends a POST to the status API of the git-air instance serving addr, with -status-token
+func postStatus(client *http.Client, addr, path string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodPost, statusURL(addr, path), nil)
+	if err != nil {
+		return nil, err
+	}
+	if statusToken != "" {
+		req.Header.Set("Authorization", "Bearer "+statusToken)
+	}
+	return client.Do(req)
+}
+
+// requestPause asks the git-air instance serving addr to pause for d
+func requestPause(addr string, d time.Duration) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := postStatus(client, addr, "/pause?for="+d.String())
+	if err != nil {
+		log.Fatalf("Pausing git-air: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.Fatalf("Pausing git-air: %s", resp.Status)
+	}
+	fmt.Printf("⏸️  git-air paused until %s\n", time.Now().Add(d).Format("15:04:05"))
+}
+
+// runStatusCommand implements "git-air -status-addr <addr> status [-json] [-watch]", showing the
+// running instance's per-repo state. Without a reachable instance it falls back to git status.
+func runStatusCommand(args []string) {
+	statusFlags := flag.NewFlagSet("status", flag.ExitOnError)
+	asJSON := statusFlags.Bool("json", false, "Print the raw JSON status")
+	watch := statusFlags.Bool("watch", false, "Refresh the table every 2 seconds")
+	statusFlags.Parse(args)
+	
+	for {
+		body, err := fetchStatus(statusAddr)
+		if err != nil {
+			if *watch {
+				log.Fatalf("Fetching status: %v", err)
+			}
+			fmt.Fprintf(os.Stderr, "⚠️  git-air is not reachable (%v), showing local git status\n", err)
+			cmd := exec.Command("git", "status", "--short", "--branch")
+			cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+			if err := cmd.Run(); err != nil {
+				os.Exit(1)
+			}
+			return
+		}
+		
+		if *watch {
+			// Move to the top left and clear the screen so the table updates in place
+			fmt.Print("\033[H\033[2J")
+		}
+		if *asJSON {
+			os.Stdout.Write(body)
+		} else if err := renderStatus(os.Stdout, body); err != nil {
+			log.Fatalf("Decoding status: %v", err)
+		}
+		
+		if !*watch {
+			return
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// fetchStatus returns the GET /status body of the git-air instance serving addr
+func fetchStatus(addr string) ([]byte, error) {
+	if addr == "" {
+		return nil, fmt.Errorf("no -status-addr given")
+	}
+	
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(statusURL(addr, "/status"))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// renderStatus writes a GET /status body as a table
+func renderStatus(w io.Writer, body []byte) error {
+	var status struct {
+		Paused bool         `json:"paused"`
+		Repos  []repoStatus `json:"repos"`
+	}
+	if err := json.Unmarshal(body, &status); err != nil {
+		return err
+	}
+	
+	if status.Paused {
+		fmt.Fprintln(w, "⏸️  Auto-sync is paused")
+	}
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "REPO\tBRANCH\tLAST COMMIT\tAHEAD\tBEHIND\tSTATUS")
+	for _, repo := range status.Repos {
+		lastCommit := "-"
+		if !repo.LastCommitAt.IsZero() {
+			lastCommit = repo.LastCommitAt.Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%d\t%d\t%s\n", filepath.Base(repo.Repo), repo.Branch, lastCommit,
+			repo.Ahead, repo.Behind, repoStatusLabel(repo))
+	}
+	return table.Flush()
+}
+
+// repoStatusLabel summarises a repository's state in one word, most serious first
+func repoStatusLabel(repo repoStatus) string {
+	switch {
+	case repo.Diverged:
+		return "diverged"
+	case len(repo.Errors) > 0:
+		return "error"
+	case repo.Inactive:
+		return "inactive"
+	case repo.Large:
+		return "too large"
+	case repo.PendingChanges:
+		return "pending"
+	default:
+		return "ok"
+	}
+}
\ No newline at end of file
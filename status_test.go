@@ -0,0 +1,36 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthorizeStatusRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		remoteAddr string
+		auth       string
+		token      string
+		wantErr    bool
+	}{
+		{"read from anywhere", "GET", "203.0.113.5:4000", "", "", false},
+		{"write from localhost", "POST", "127.0.0.1:4000", "", "", false},
+		{"write from localhost over IPv6", "DELETE", "[::1]:4000", "", "", false},
+		{"write from another host", "POST", "203.0.113.5:4000", "", "", true},
+		{"write with the token", "POST", "203.0.113.5:4000", "Bearer s3cret", "s3cret", false},
+		{"write with a wrong token", "POST", "203.0.113.5:4000", "Bearer guess", "s3cret", true},
+		{"write without the token from localhost", "POST", "127.0.0.1:4000", "", "s3cret", true},
+		{"read without the token", "GET", "203.0.113.5:4000", "", "s3cret", false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(tt.method, "/sync/api", nil)
+		r.RemoteAddr = tt.remoteAddr
+		if tt.auth != "" {
+			r.Header.Set("Authorization", tt.auth)
+		}
+		if err := authorizeStatusRequest(r, tt.token); (err != nil) != tt.wantErr {
+			t.Errorf("%s: authorizeStatusRequest() = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
\ No newline at end of file
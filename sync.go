@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// syncResult describes what an on-demand sync did
+type syncResult struct {
+	Repo      string        `json:"repo"`
+	CommitSHA string        `json:"commitSha,omitempty"`
+	PushedTo  []string      `json:"pushedTo"`
+	Duration  time.Duration `json:"duration"`
+}
+
+var (
+	errSyncPaused   = errors.New("auto-sync is paused")
+	errSyncInCI     = errors.New("running under CI, auto-commit is disabled (see -allow-in-ci)")
+	errSyncFollower = errors.New("another instance holds the leader lock")
+)
+
+// syncBlocked returns why commits and pushes are held back right now, the same checks the main
+// loop makes, or nil when they may run
+func syncBlocked() error {
+	switch {
+	case pause.isPaused():
+		return errSyncPaused
+	case inCI:
+		return errSyncInCI
+	case !isLeader():
+		return errSyncFollower
+	}
+	return nil
+}
+
+// forceSync runs a commit and push of repoPath right away instead of waiting for the next pass,
+// unless syncBlocked says it mustn't
+func forceSync(repoPath string) (syncResult, error) {
+	if err := syncBlocked(); err != nil {
+		return syncResult{}, err
+	}
+	
+	start := time.Now()
+	before := headSHAIn(repoPath)
+	
+	processRepo(repoPath)
+	
+	result := syncResult{Repo: repoPath, PushedTo: []string{}, Duration: time.Since(start)}
+	if after := headSHAIn(repoPath); after != before {
+		result.CommitSHA = after
+	}
+	for _, repo := range state.snapshot() {
+		if repo.Repo == repoPath && repo.LastPushAt.After(start) {
+			result.PushedTo = repo.LastPushedTo
+		}
+	}
+	return result, nil
+}
+
+// headSHAIn returns the HEAD commit of the repo at repoPath without changing directory
+func headSHAIn(repoPath string) string {
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// syncHandler serves POST /sync/<repo>, where repo is a URL-encoded directory name or path
+func syncHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	
+	name, err := url.PathUnescape(strings.TrimPrefix(r.URL.EscapedPath(), "/sync/"))
+	if err != nil {
+		http.Error(w, "invalid repository "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	repoPath, ok := findRepo(name)
+	if !ok {
+		http.Error(w, "unknown repository "+name, http.StatusNotFound)
+		return
+	}
+	
+	if err := syncBlocked(); err != nil {
+		http.Error(w, "not synced: "+err.Error(), http.StatusConflict)
+		return
+	}
+	
+	fmt.Printf("🔄 Forced sync of %s\n", repoPath)
+	result, err := forceSync(repoPath)
+	if err != nil {
+		http.Error(w, "not synced: "+err.Error(), http.StatusConflict)
+		return
+	}
+	writeJSON(w, result)
+}
+
+// repoTags returns the labels of the repository at repoPath, set with "git config --add git-air.tag <tag>"
+func repoTags(repoPath string) []string {
+	output, _ := gitIn(repoPath, "config", "--get-all", "git-air.tag").Output()
+	return strings.Fields(string(output))
+}
+
+// groupReposByTag maps each tag to the repositories carrying it
+func groupReposByTag(repos []string) map[string][]string {
+	groups := map[string][]string{}
+	for _, repo := range repos {
+		for _, tag := range repoTags(repo) {
+			groups[tag] = append(groups[tag], repo)
+		}
+	}
+	return groups
+}
+
+// tagSyncHandler serves POST /sync/tag/<tag>, force-syncing every repository with that tag
+func tagSyncHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	
+	tag := strings.TrimPrefix(r.URL.Path, "/sync/tag/")
+	var repos []string
+	for _, repo := range state.snapshot() {
+		repos = append(repos, repo.Repo)
+	}
+	tagged := groupReposByTag(repos)[tag]
+	if len(tagged) == 0 {
+		http.Error(w, "no repository tagged "+tag, http.StatusNotFound)
+		return
+	}
+	if err := syncBlocked(); err != nil {
+		http.Error(w, "not synced: "+err.Error(), http.StatusConflict)
+		return
+	}
+	
+	fmt.Printf("🔄 Forced sync of %d repos tagged %s\n", len(tagged), tag)
+	results := []syncResult{}
+	for _, repo := range tagged {
+		result, err := forceSync(repo)
+		if err != nil {
+			http.Error(w, "not synced: "+err.Error(), http.StatusConflict)
+			return
+		}
+		results = append(results, result)
+	}
+	writeJSON(w, results)
+}
+
+// runForceSyncCommand implements "git-air -status-addr <addr> force-sync <repo>" and
+// "force-sync -tag <tag>" for every repository with a tag
+func runForceSyncCommand(args []string) {
+	syncFlags := flag.NewFlagSet("force-sync", flag.ExitOnError)
+	tag := syncFlags.String("tag", "", "Sync every repository tagged with this git-air.tag instead of one repo")
+	syncFlags.Parse(args)
+	
+	path, target := "", ""
+	switch {
+	case *tag != "" && syncFlags.NArg() == 0:
+		path, target = "/sync/tag/"+url.PathEscape(*tag), "tag "+*tag
+	case *tag == "" && syncFlags.NArg() == 1:
+		path, target = "/sync/"+url.PathEscape(syncFlags.Arg(0)), syncFlags.Arg(0)
+	default:
+		log.Fatal("usage: git-air -status-addr <addr> force-sync <repo> | force-sync -tag <tag>")
+	}
+	if statusAddr == "" {
+		log.Fatal("force-sync needs -status-addr of the running git-air instance")
+	}
+	
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := postStatus(client, statusAddr, path)
+	if err != nil {
+		log.Fatalf("Syncing %s: %v", target, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		reason, _ := io.ReadAll(resp.Body)
+		log.Fatalf("Syncing %s: %s: %s", target, resp.Status, strings.TrimSpace(string(reason)))
+	}
+	
+	var results []syncResult
+	if *tag != "" {
+		err = json.NewDecoder(resp.Body).Decode(&results)
+	} else {
+		var result syncResult
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		results = append(results, result)
+	}
+	if err != nil {
+		log.Fatalf("Decoding sync result: %v", err)
+	}
+	for _, result := range results {
+		printSyncResult(result)
+	}
+}
+
+// printSyncResult reports what a force-sync did to one repository
+func printSyncResult(result syncResult) {
+	if result.CommitSHA != "" {
+		fmt.Printf("📝 Committed %.7s\n", result.CommitSHA)
+	} else {
+		fmt.Println("📝 Nothing to commit")
+	}
+	if len(result.PushedTo) > 0 {
+		fmt.Printf("🚀 Pushed to %s\n", strings.Join(result.PushedTo, ", "))
+	}
+	fmt.Printf("✅ %s synced in %s\n", result.Repo, result.Duration.Round(time.Millisecond))
+}
\ No newline at end of file
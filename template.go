@@ -0,0 +1,198 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// commitTemplateData holds the values available to -commit-template
+type commitTemplateData struct {
+	Timestamp    string
+	Branch       string
+	FilesChanged int
+	RepoName     string
+	Remote       string
+	DiffStat     diffStat
+	StagedFiles  []stagedFile
+}
+
+// stagedFile is one entry of the staging area: Status is git's letter (A, M, D, T)
+type stagedFile struct {
+	Path         string
+	Status       string
+	LinesAdded   int
+	LinesDeleted int
+}
+
+// String renders the file as "M  path (+3/-1)"
+func (f stagedFile) String() string {
+	return fmt.Sprintf("%-2s %s (+%d/-%d)", f.Status, f.Path, f.LinesAdded, f.LinesDeleted)
+}
+
+// getStagingAreaContents lists what the next commit of the current repo will contain. git can't
+// combine --name-status and --numstat, so both are read and joined on the path.
+func getStagingAreaContents() ([]stagedFile, error) {
+	cmd := exec.Command("git", "diff", "--cached", "--no-renames", "--name-status", "-z")
+	statuses, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	cmd = exec.Command("git", "diff", "--cached", "--no-renames", "--numstat", "-z")
+	numstat, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseStagedFiles(string(statuses), string(numstat)), nil
+}
+
+// parseStagedFiles joins NUL-separated --name-status ("M\0path\0") and --numstat
+// ("3\t1\tpath\0") output; binary files report "-" and count as no lines
+func parseStagedFiles(statuses, numstat string) []stagedFile {
+	lines := map[string][2]int{}
+	for _, entry := range strings.Split(numstat, "\x00") {
+		fields := strings.SplitN(entry, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		added, _ := strconv.Atoi(fields[0])
+		deleted, _ := strconv.Atoi(fields[1])
+		lines[fields[2]] = [2]int{added, deleted}
+	}
+	
+	var files []stagedFile
+	fields := strings.Split(strings.TrimSuffix(statuses, "\x00"), "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		path := fields[i+1]
+		files = append(files, stagedFile{
+			Path:         path,
+			Status:       fields[i],
+			LinesAdded:   lines[path][0],
+			LinesDeleted: lines[path][1],
+		})
+	}
+	return files
+}
+
+// diffStat summarises the staged changes, e.g. for {{.DiffStat.Insertions}} in templates
+type diffStat struct {
+	FilesChanged int
+	Insertions   int
+	Deletions    int
+	Files        []string
+}
+
+// getDiffStat summarises the staged changes of the current repo
+func getDiffStat() (diffStat, error) {
+	cmd := exec.Command("git", "diff", "--cached", "--numstat")
+	output, err := cmd.Output()
+	if err != nil {
+		return diffStat{}, err
+	}
+	return parseNumstat(string(output)), nil
+}
+
+// parseNumstat adds up git diff --numstat lines; binary files ("-") count as changed with no lines
+func parseNumstat(output string) diffStat {
+	var stat diffStat
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		insertions, _ := strconv.Atoi(fields[0])
+		deletions, _ := strconv.Atoi(fields[1])
+		stat.FilesChanged++
+		stat.Insertions += insertions
+		stat.Deletions += deletions
+		stat.Files = append(stat.Files, fields[2])
+	}
+	return stat
+}
+
+// String renders the summary appended by -diffstat-in-message, e.g. "+12/-3 in 2 files"
+func (d diffStat) String() string {
+	return fmt.Sprintf("+%d/-%d in %d files", d.Insertions, d.Deletions, d.FilesChanged)
+}
+
+// renderCommitMessage renders a text/template commit message, e.g.
+// "[auto] {{.FilesChanged}} files changed on {{.Branch}} at {{.Timestamp}}"
+func renderCommitMessage(tmpl string, data commitTemplateData) (string, error) {
+	t, err := template.New("commit").Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+	
+	var msg strings.Builder
+	if err := t.Execute(&msg, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(msg.String()), nil
+}
+
+// conventionalCommitMessage builds "<type>(auto): <summary> - <timestamp>" from the staged changes
+func conventionalCommitMessage(timestamp string) string {
+	cmd := exec.Command("git", "diff", "--cached", "--numstat")
+	output, _ := cmd.Output()
+	
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	summary := fmt.Sprintf("update %d files", len(lines))
+	if len(lines) == 1 {
+		if fields := strings.Split(lines[0], "\t"); len(fields) == 3 {
+			summary = "update " + fields[2]
+		}
+	}
+	return fmt.Sprintf("%s(auto): %s - %s", inferCommitType(string(output)), summary, timestamp)
+}
+
+// inferCommitType picks a Conventional Commits type from git diff --numstat output:
+// docs when only documentation changed, test when only tests changed, otherwise
+// feat for pure additions and fix when lines were also removed
+func inferCommitType(status string) string {
+	allDocs, allTests, additionsOnly := true, true, true
+	files := 0
+	for _, line := range strings.Split(strings.TrimSpace(status), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 {
+			continue
+		}
+		files++
+		
+		path := fields[2]
+		if !isDocFile(path) {
+			allDocs = false
+		}
+		if !strings.HasSuffix(path, "_test.go") {
+			allTests = false
+		}
+		// Binary files report "-" instead of line counts
+		if fields[1] != "0" && fields[1] != "-" {
+			additionsOnly = false
+		}
+	}
+	
+	switch {
+	case files == 0:
+		return "chore"
+	case allDocs:
+		return "docs"
+	case allTests:
+		return "test"
+	case additionsOnly:
+		return "feat"
+	default:
+		return "fix"
+	}
+}
+
+// isDocFile reports whether path is documentation: Markdown/text files or anything under docs/
+func isDocFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown", ".rst", ".txt", ".adoc":
+		return true
+	}
+	return strings.HasPrefix(path, "docs/") || strings.Contains(path, "/docs/")
+}
\ No newline at end of file
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInferCommitType(t *testing.T) {
+	tests := []struct {
+		name    string
+		numstat string
+		want    string
+	}{
+		{"nothing staged", "", "chore"},
+		{"readme only", "3\t1\tREADME.md\n", "docs"},
+		{"docs directory", "10\t0\tdocs/setup.html\n2\t2\tapi/docs/index.html\n", "docs"},
+		{"tests only", "12\t4\tmain_test.go\n3\t0\tpkg/util_test.go\n", "test"},
+		{"additions only", "20\t0\tmain.go\n5\t0\tREADME.md\n", "feat"},
+		{"binary addition", "-\t-\tlogo.png\n", "feat"},
+		{"lines removed", "4\t2\tmain.go\n", "fix"},
+		{"mixed tests and code", "3\t0\tmain_test.go\n1\t1\tmain.go\n", "fix"},
+	}
+	for _, tt := range tests {
+		if got := inferCommitType(tt.numstat); got != tt.want {
+			t.Errorf("%s: inferCommitType(%q) = %q, want %q", tt.name, tt.numstat, got, tt.want)
+		}
+	}
+}
+
+func TestParseStagedFiles(t *testing.T) {
+	statuses := "M\x00main.go\x00A\x00docs/setup guide.md\x00D\x00old.txt\x00A\x00logo.png\x00"
+	numstat := "4\t1\tmain.go\x0012\t0\tdocs/setup guide.md\x000\t7\told.txt\x00-\t-\tlogo.png\x00"
+	want := []stagedFile{
+		{Path: "main.go", Status: "M", LinesAdded: 4, LinesDeleted: 1},
+		{Path: "docs/setup guide.md", Status: "A", LinesAdded: 12},
+		{Path: "old.txt", Status: "D", LinesDeleted: 7},
+		{Path: "logo.png", Status: "A"},
+	}
+	got := parseStagedFiles(statuses, numstat)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseStagedFiles = %v, want %v", got, want)
+	}
+	if s := got[0].String(); s != "M  main.go (+4/-1)" {
+		t.Errorf("String() = %q, want %q", s, "M  main.go (+4/-1)")
+	}
+	if got := parseStagedFiles("", ""); len(got) != 0 {
+		t.Errorf("parseStagedFiles of nothing staged = %v, want none", got)
+	}
+}
\ No newline at end of file
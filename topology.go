@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+var errCircularDependency = errors.New("circular dependency between repositories")
+
+// topologicalOrder sorts repos so that every repository comes before the ones containing it,
+// either as a submodule listed in .gitmodules or as a nested repo, using Kahn's algorithm.
+// Unrelated repos keep their relative order.
+func topologicalOrder(repos []string) ([]string, error) {
+	index := map[string]int{}
+	for i, repo := range repos {
+		index[filepath.Clean(repo)] = i
+	}
+	
+	// parents[i] are the repos that must wait for repos[i]
+	parents := make([][]int, len(repos))
+	pending := make([]int, len(repos))
+	addEdge := func(child, parent int) {
+		parents[child] = append(parents[child], parent)
+		pending[parent]++
+	}
+	
+	for i, repo := range repos {
+		children := map[int]bool{}
+		entries, _ := parseGitmodules(filepath.Join(repo, ".gitmodules"))
+		for _, entry := range entries {
+			if j, ok := index[filepath.Join(repo, entry.Path)]; ok && j != i {
+				children[j] = true
+			}
+		}
+		for j, other := range repos {
+			if j != i && isInside(other, repo) {
+				children[j] = true
+			}
+		}
+		for j := range children {
+			addEdge(j, i)
+		}
+	}
+	
+	var ready []int
+	for i := range repos {
+		if pending[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+	
+	ordered := make([]string, 0, len(repos))
+	for len(ready) > 0 {
+		// Take the earliest ready repo so unrelated repos keep their scan order
+		next := 0
+		for k := range ready {
+			if ready[k] < ready[next] {
+				next = k
+			}
+		}
+		i := ready[next]
+		ready = append(ready[:next], ready[next+1:]...)
+		
+		ordered = append(ordered, repos[i])
+		for _, parent := range parents[i] {
+			if pending[parent]--; pending[parent] == 0 {
+				ready = append(ready, parent)
+			}
+		}
+	}
+	
+	if len(ordered) != len(repos) {
+		return nil, errCircularDependency
+	}
+	return ordered, nil
+}
+
+// isInside reports whether path lies below dir
+func isInside(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, "../") && !filepath.IsAbs(rel)
+}
+
+// orderRepos returns repos leaves-first, keeping the scan order if the dependencies form a cycle
+func orderRepos(repos []string) []string {
+	ordered, err := topologicalOrder(repos)
+	if err != nil {
+		fmt.Printf("⚠️  Can't order repositories: %v\n", err)
+		return repos
+	}
+	return ordered
+}
\ No newline at end of file
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+var (
+	errNotAutoCommit = errors.New("last commit was not made by git-air")
+	errAlreadyPushed = errors.New("last commit has already been pushed")
+)
+
+// isAutoCommitMessage reports whether a full commit message carries autoCommitTrailer. Commits
+// made before the trailer existed are recognised by the default and Conventional Commits subjects.
+func isAutoCommitMessage(message string) bool {
+	for _, line := range strings.Split(message, "\n") {
+		if strings.EqualFold(strings.TrimSpace(line), autoCommitTrailer) {
+			return true
+		}
+	}
+	
+	subject, _, _ := strings.Cut(message, "\n")
+	subject = strings.TrimPrefix(subject, "[shutdown] ")
+	subject = strings.TrimPrefix(subject, "[after quiet hours] ")
+	return strings.HasPrefix(subject, "auto commit") || strings.Contains(subject, "(auto): ")
+}
+
+// isAutoCommit reports whether git-air made the commit rev of the current repo
+func isAutoCommit(rev string) bool {
+	cmd := exec.Command("git", "log", "-1", "--format=%B", rev)
+	output, err := cmd.Output()
+	return err == nil && isAutoCommitMessage(strings.TrimSpace(string(output)))
+}
+
+// undoLastAutoCommit removes the last commit of the current repo if git-air made it and it
+// hasn't been pushed. A soft reset keeps its changes staged; hard discards them.
+func undoLastAutoCommit(hard bool) error {
+	if !isAutoCommit("HEAD") {
+		return errNotAutoCommit
+	}
+	
+	pushed, err := isHeadPushed()
+	if err != nil {
+		return err
+	}
+	if pushed {
+		return errAlreadyPushed
+	}
+	
+	mode := "--soft"
+	if hard {
+		mode = "--hard"
+	}
+	cmd := exec.Command("git", "reset", mode, "HEAD~1")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git reset %s HEAD~1: %s", mode, gitErrorLine(output, err))
+	}
+	return nil
+}
+
+// isHeadPushed reports whether any remote-tracking branch contains HEAD, meaning someone may already have it
+func isHeadPushed() (bool, error) {
+	cmd := exec.Command("git", "branch", "-r", "--contains", "HEAD")
+	output, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("checking remote branches: %v", err)
+	}
+	return strings.TrimSpace(string(output)) != "", nil
+}
+
+// runUndoCommand implements "git-air undo-last [-hard] [repo]"
+func runUndoCommand(args []string) {
+	undoFlags := flag.NewFlagSet("undo-last", flag.ExitOnError)
+	hard := undoFlags.Bool("hard", false, "Discard the changes of the undone commit instead of keeping them staged")
+	undoFlags.Parse(args)
+	
+	if repo := undoFlags.Arg(0); repo != "" {
+		if err := os.Chdir(repo); err != nil {
+			log.Fatal(err)
+		}
+	}
+	
+	subject, _ := exec.Command("git", "log", "-1", "--format=%h %s").Output()
+	if err := undoLastAutoCommit(*hard); err != nil {
+		log.Fatalf("Not undoing %s: %v", strings.TrimSpace(string(subject)), err)
+	}
+	fmt.Printf("↩️  Undid %s\n", strings.TrimSpace(string(subject)))
+}
\ No newline at end of file
@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestIsAutoCommitMessage(t *testing.T) {
+	tests := []struct {
+		message string
+		want    bool
+	}{
+		{"auto commit - 3 files changed - 2024-03-04 09:30:00\n\nGit-Air: auto", true},
+		{"docs: auto commit - 2 files changed\n\nGit-Air: auto", true},
+		{"Deploy notes for api\n\nRendered from -commit-template\n\nGit-Air: auto", true},
+		{"[shutdown] wip\n\ngit-air: AUTO", true},
+		// Commits made before the trailer existed
+		{"auto commit - 3 files changed - 2024-03-04 09:30:00", true},
+		{"[shutdown] auto commit - 1 file changed", true},
+		{"[after quiet hours] chore(auto): 2 files changed", true},
+		{"Fix login redirect", false},
+		{"Fix login redirect\n\nMentions Git-Air: auto in a sentence", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isAutoCommitMessage(tt.message); got != tt.want {
+			t.Errorf("isAutoCommitMessage(%q) = %v, want %v", tt.message, got, tt.want)
+		}
+	}
+}
\ No newline at end of file
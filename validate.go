@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
+// minScanInterval keeps rescans from walking the whole tree every pass
+const minScanInterval = 30 * time.Second
+
+// validateFlags checks the numeric and pattern options after flag.Parse. A negative timeout or
+// a zero retry count doesn't fail anywhere on its own, it just makes git-air misbehave quietly,
+// so every problem is collected and reported together before the daemon starts.
+func validateFlags() []error {
+	var errs []error
+	
+	if scanInterval < minScanInterval {
+		errs = append(errs, fmt.Errorf("-scan-interval must be at least %s, got %s", minScanInterval, scanInterval))
+	}
+	for _, d := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"-network-timeout", networkTimeout},
+		{"-pre-commit-hook-timeout", hookTimeout},
+		{"-batch-window", batchWindow},
+	} {
+		if d.value <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be greater than 0, got %s", d.name, d.value))
+		}
+	}
+	for _, d := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"-debounce-window", debounceWindow},
+		{"-amend-window", amendWindow},
+		{"-min-commit-gap", minCommitGap},
+		{"-push-retry-base-delay", pushRetryDelay},
+		{"-branch-cache-ttl", branchCacheTTL},
+		{"-scan-cache-ttl", scanCacheTTL},
+		{"-drain-timeout", drainTimeout},
+		{"-fsck-interval", fsckInterval},
+		{"-inactive-threshold", inactiveAfter},
+	} {
+		if d.value < 0 {
+			errs = append(errs, fmt.Errorf("%s must be 0 or greater, got %s", d.name, d.value))
+		}
+	}
+	
+	for _, n := range []struct {
+		name       string
+		value, min int64
+	}{
+		{"-push-retry-attempts", int64(pushRetries), 1},
+		{"-push-concurrency", int64(pushConcurrency), 1},
+		{"-max-concurrent-ops", int64(maxConcurrentOps), 1},
+		{"-squash-after", int64(squashAfter), 1},
+		{"-tag-every", int64(tagEvery), 0},
+		{"-max-file-size-bytes", maxFileSize, 0},
+		{"-lfs-max-file-size-mb", int64(lfsMaxFileSizeMB), 0},
+		{"-max-ahead-before-push", int64(maxAheadPush), 0},
+		{"-max-unpushed-commits", int64(maxUnpushed), 0},
+		{"-max-scan-depth", int64(maxScanDepth), 0},
+		{"-max-repo-size-mb", maxRepoSizeMB, 0},
+	} {
+		if n.value < n.min {
+			errs = append(errs, fmt.Errorf("%s must be at least %d, got %d", n.name, n.min, n.value))
+		}
+	}
+	
+	for _, list := range []struct {
+		name     string
+		patterns []string
+	}{
+		{"-allow-branches", allowedBranches},
+		{"-block-branches", blockedBranches},
+		{"-protected-branches", protectedBranches},
+		{"-mirror-only-branches", mirrorBranches},
+		{"-include-paths", includePaths},
+		{"-scan-exclude", scanExcludes},
+	} {
+		for _, pattern := range list.patterns {
+			if _, err := filepath.Match(pattern, ""); err != nil {
+				errs = append(errs, fmt.Errorf("%s: invalid pattern %q: %v", list.name, pattern, err))
+			}
+		}
+	}
+	return errs
+}
\ No newline at end of file
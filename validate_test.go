@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// setDefaultFlags puts every option validateFlags checks back to its default
+func setDefaultFlags() {
+	debounceWindow = 2 * time.Second
+	scanInterval, networkTimeout, hookTimeout, batchWindow = 5*time.Minute, 30*time.Second, 30*time.Second, time.Minute
+	amendWindow, minCommitGap, pushRetryDelay, branchCacheTTL, scanCacheTTL = 0, 6*time.Second, 5*time.Second, time.Hour, 10*time.Minute
+	drainTimeout, fsckInterval, inactiveAfter = 30*time.Second, 24*time.Hour, 0
+	pushRetries, pushConcurrency, maxConcurrentOps, squashAfter = 3, 3, 8, 5
+	tagEvery, maxFileSize, lfsMaxFileSizeMB, maxAheadPush, maxUnpushed, maxScanDepth, maxRepoSizeMB = 0, 0, 0, 0, 50, 5, 0
+	allowedBranches, blockedBranches, mirrorBranches, includePaths, scanExcludes = nil, nil, nil, nil, nil
+	protectedBranches = []string{"main", "master", "release/*"}
+}
+
+func TestValidateFlags(t *testing.T) {
+	defer setDefaultFlags()
+	
+	setDefaultFlags()
+	if errs := validateFlags(); len(errs) != 0 {
+		t.Fatalf("defaults rejected: %v", errs)
+	}
+	
+	tests := []struct {
+		name  string
+		set   func()
+		issue string
+	}{
+		{"negative scan interval", func() { scanInterval = -time.Second }, "-scan-interval"},
+		{"scan interval under 30s", func() { scanInterval = 10 * time.Second }, "-scan-interval"},
+		{"zero network timeout", func() { networkTimeout = 0 }, "-network-timeout"},
+		{"negative hook timeout", func() { hookTimeout = -time.Second }, "-pre-commit-hook-timeout"},
+		{"zero batch window", func() { batchWindow = 0 }, "-batch-window"},
+		{"negative debounce window", func() { debounceWindow = -time.Second }, "-debounce-window"},
+		{"negative amend window", func() { amendWindow = -time.Minute }, "-amend-window"},
+		{"negative commit gap", func() { minCommitGap = -time.Second }, "-min-commit-gap"},
+		{"negative scan cache TTL", func() { scanCacheTTL = -time.Minute }, "-scan-cache-ttl"},
+		{"negative fsck interval", func() { fsckInterval = -time.Hour }, "-fsck-interval"},
+		{"negative inactive threshold", func() { inactiveAfter = -time.Hour }, "-inactive-threshold"},
+		{"zero push retries", func() { pushRetries = 0 }, "-push-retry-attempts"},
+		{"zero push concurrency", func() { pushConcurrency = 0 }, "-push-concurrency"},
+		{"zero concurrent ops", func() { maxConcurrentOps = 0 }, "-max-concurrent-ops"},
+		{"zero squash threshold", func() { squashAfter = 0 }, "-squash-after"},
+		{"negative tag interval", func() { tagEvery = -1 }, "-tag-every"},
+		{"negative file size limit", func() { maxFileSize = -1 }, "-max-file-size-bytes"},
+		{"negative scan depth", func() { maxScanDepth = -2 }, "-max-scan-depth"},
+		{"bad branch pattern", func() { protectedBranches = []string{"release/["} }, "-protected-branches"},
+		{"bad exclude pattern", func() { scanExcludes = []string{"build/[a-"} }, "-scan-exclude"},
+	}
+	for _, tt := range tests {
+		setDefaultFlags()
+		tt.set()
+		errs := validateFlags()
+		if len(errs) != 1 || !strings.HasPrefix(errs[0].Error(), tt.issue) {
+			t.Errorf("%s: validateFlags() = %v, want one %s error", tt.name, errs, tt.issue)
+		}
+	}
+}
+
+func TestValidateFlagsReportsEveryProblem(t *testing.T) {
+	defer setDefaultFlags()
+	
+	setDefaultFlags()
+	scanInterval, pushRetries, includePaths = 0, 0, []string{"src/[", "docs/\\"}
+	if errs := validateFlags(); len(errs) != 4 {
+		t.Errorf("validateFlags() = %v, want 4 errors", errs)
+	}
+}
\ No newline at end of file
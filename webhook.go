@@ -0,0 +1,147 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"path"
+	"strings"
+)
+
+var (
+	errBadSignature = errors.New("signature does not match -webhook-secret")
+	errNoSecret     = errors.New("no -webhook-secret configured, refusing unauthenticated events")
+)
+
+// pushEvent holds the fields git-air reads from GitHub, GitLab and Gitea push payloads
+type pushEvent struct {
+	Repository struct {
+		Name string `json:"name"`
+	} `json:"repository"`
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+}
+
+// startWebhookReceiver serves POST /webhook/sync on addr so forge push events trigger a sync
+func startWebhookReceiver(addr string) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatalf("Webhook receiver: %v", err)
+	}
+	
+	mux := http.NewServeMux()
+	mux.HandleFunc("/webhook/sync", webhookSyncHandler)
+	
+	fmt.Printf("🪝 Webhook receiver listening on %s\n", listener.Addr())
+	go func() {
+		if err := http.Serve(listener, mux); err != nil {
+			log.Printf("Webhook receiver stopped: %v", err)
+		}
+	}()
+}
+
+// webhookSyncHandler serves POST /webhook/sync. It answers 202 straight away and pulls, commits
+// and pushes the repository named in the push event in the background.
+func webhookSyncHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	
+	body, err := io.ReadAll(io.LimitReader(r.Body, 10<<20))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := verifyWebhook(r.Header, body, webhookSecret); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	name, err := pushEventRepo(r.Header, body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	repoPath, ok := findRepo(name)
+	if !ok {
+		http.Error(w, "unknown repository "+name, http.StatusNotFound)
+		return
+	}
+	
+	if err := syncBlocked(); err != nil {
+		http.Error(w, "not synced: "+err.Error(), http.StatusConflict)
+		return
+	}
+	
+	fmt.Printf("🪝 Push event for %s, syncing\n", repoPath)
+	go func() {
+		pullUpdates(repoPath)
+		if _, err := forceSync(repoPath); err != nil {
+			fmt.Printf("  ⚠️  Push event for %s not synced: %v\n", repoPath, err)
+		}
+	}()
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// verifyWebhook checks the request against secret: GitLab sends it as X-Gitlab-Token, Gitea signs
+// the body with HMAC-SHA256 in X-Gitea-Signature and GitHub in X-Hub-Signature-256 (or the older
+// SHA-1 X-Hub-Signature). Without a secret every request is rejected.
+func verifyWebhook(header http.Header, body []byte, secret string) error {
+	if secret == "" {
+		return errNoSecret
+	}
+	
+	switch {
+	case header.Get("X-Gitlab-Event") != "":
+		if subtle.ConstantTimeCompare([]byte(header.Get("X-Gitlab-Token")), []byte(secret)) != 1 {
+			return errBadSignature
+		}
+		return nil
+	case header.Get("X-Gitea-Event") != "":
+		return checkHMAC(sha256.New, secret, body, header.Get("X-Gitea-Signature"))
+	case header.Get("X-Hub-Signature-256") != "":
+		return checkHMAC(sha256.New, secret, body, strings.TrimPrefix(header.Get("X-Hub-Signature-256"), "sha256="))
+	default:
+		return checkHMAC(sha1.New, secret, body, strings.TrimPrefix(header.Get("X-Hub-Signature"), "sha1="))
+	}
+}
+
+// checkHMAC compares a hex-encoded HMAC of body with signature
+func checkHMAC(newHash func() hash.Hash, secret string, body []byte, signature string) error {
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+		return errBadSignature
+	}
+	return nil
+}
+
+// pushEventRepo returns the repository name of a push event: project.path_with_namespace for GitLab
+// (its last segment), repository.name for GitHub and Gitea
+func pushEventRepo(header http.Header, body []byte) (string, error) {
+	var event pushEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return "", fmt.Errorf("invalid JSON payload: %v", err)
+	}
+	
+	name := event.Repository.Name
+	if header.Get("X-Gitlab-Event") != "" {
+		name = path.Base(event.Project.PathWithNamespace)
+	}
+	if name == "" || name == "." {
+		return "", errors.New("payload names no repository")
+	}
+	return name, nil
+}
\ No newline at end of file